@@ -9,11 +9,14 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	
 	"github.com/gorilla/websocket"
 	"tcr-game/internal/auth"
+	"tcr-game/pkg/protocol"
 )
 
 type TestClient struct {
@@ -22,6 +25,8 @@ type TestClient struct {
 	gameID    string
 	playerID  string
 	wsConn    *websocket.Conn
+	wsMutex   sync.Mutex // one writer at a time on wsConn
+	requests  int
 }
 
 func main() {
@@ -33,7 +38,7 @@ func main() {
 	}
 	
 	fmt.Println("TCR Game Test Client")
-	fmt.Println("Commands: register, login, create, join, attack, state, ws, quit")
+	fmt.Println("Commands: register, login, create, join, attack, spawn, state, ws, quit")
 	fmt.Println("Example: login username password")
 	
 	scanner := bufio.NewScanner(os.Stdin)
@@ -67,11 +72,15 @@ func main() {
 			}
 			client.login(parts[1], parts[2])
 		case "create":
-			if len(parts) != 3 {
-				fmt.Println("Usage: create <mode> <gameID>")
+			if len(parts) != 3 && len(parts) != 4 {
+				fmt.Println("Usage: create <mode> <gameID> [players]")
 				continue
 			}
-			client.createGame(parts[1], parts[2])
+			players := 0
+			if len(parts) == 4 {
+				players, _ = strconv.Atoi(parts[3])
+			}
+			client.createGame(parts[1], parts[2], players)
 		case "join":
 			if len(parts) != 2 {
 				fmt.Println("Usage: join <gameID>")
@@ -79,11 +88,21 @@ func main() {
 			}
 			client.joinGame(parts[1])
 		case "attack":
+			if len(parts) != 3 && len(parts) != 4 {
+				fmt.Println("Usage: attack <troopID> <towerIndex> [targetPlayerID]")
+				continue
+			}
+			targetPlayer := ""
+			if len(parts) == 4 {
+				targetPlayer = parts[3]
+			}
+			client.attack(parts[1], parts[2], targetPlayer)
+		case "spawn":
 			if len(parts) != 3 {
-				fmt.Println("Usage: attack <troopID> <towerIndex>")
+				fmt.Println("Usage: spawn <troopID> <towerIndex>")
 				continue
 			}
-			client.attack(parts[1], parts[2])
+			client.spawn(parts[1], parts[2])
 		case "state":
 			client.getGameState()
 		case "ws":
@@ -162,16 +181,19 @@ func (c *TestClient) login(username, password string) {
 	}
 }
 
-func (c *TestClient) createGame(mode, gameID string) {
+func (c *TestClient) createGame(mode, gameID string, players int) {
 	if c.token == "" {
 		fmt.Println("Please login first")
 		return
 	}
 	
-	gameData := map[string]string{
+	gameData := map[string]interface{}{
 		"mode":    mode,
 		"game_id": gameID,
 	}
+	if players > 0 {
+		gameData["players"] = players
+	}
 	
 	data, _ := json.Marshal(gameData)
 	req, _ := http.NewRequest("POST", c.serverURL+"/api/games", bytes.NewBuffer(data))
@@ -225,7 +247,7 @@ func (c *TestClient) joinGame(gameID string) {
 	}
 }
 
-func (c *TestClient) attack(troopID, towerIndex string) {
+func (c *TestClient) attack(troopID, towerIndex, targetPlayer string) {
 	if c.token == "" || c.gameID == "" {
 		fmt.Println("Please login and join a game first")
 		return
@@ -239,11 +261,26 @@ func (c *TestClient) attack(troopID, towerIndex string) {
 		towerIdx = 2
 	}
 	
+	// Over the game's WebSocket, the reply arrives as an ack
+	if c.wsConn != nil {
+		c.sendRequest(protocol.SimpleActionMessage{
+			Type:         protocol.MsgTypeSimpleAction,
+			RequestID:    c.nextRequestID(),
+			TroopID:      troopID,
+			TargetPlayer: targetPlayer,
+			TargetTower:  towerIdx,
+		})
+		return
+	}
+	
 	actionData := map[string]interface{}{
 		"type":        "attack",
 		"troop_id":    troopID,
 		"target_tower": towerIdx,
 	}
+	if targetPlayer != "" {
+		actionData["target_player"] = targetPlayer
+	}
 	
 	data, _ := json.Marshal(actionData)
 	req, _ := http.NewRequest("POST", c.serverURL+"/api/games/"+c.gameID+"/action", bytes.NewBuffer(data))
@@ -272,6 +309,37 @@ func (c *TestClient) attack(troopID, towerIndex string) {
 	}
 }
 
+// spawn deploys a troop in an enhanced game over its WebSocket
+func (c *TestClient) spawn(troopID, towerIndex string) {
+	if c.wsConn == nil {
+		fmt.Println("Please connect the WebSocket first")
+		return
+	}
+	
+	towerIdx, _ := strconv.Atoi(towerIndex)
+	c.sendRequest(protocol.EnhancedActionMessage{
+		Type:        protocol.MsgTypeEnhancedAction,
+		RequestID:   c.nextRequestID(),
+		TroopID:     troopID,
+		TargetTower: towerIdx,
+	})
+}
+
+func (c *TestClient) nextRequestID() string {
+	c.requests++
+	return strconv.Itoa(c.requests)
+}
+
+// sendRequest sends a message over the game's WebSocket
+func (c *TestClient) sendRequest(message interface{}) {
+	c.wsMutex.Lock()
+	defer c.wsMutex.Unlock()
+	
+	if err := c.wsConn.WriteJSON(message); err != nil {
+		fmt.Printf("WebSocket write error: %v\n", err)
+	}
+}
+
 func (c *TestClient) getGameState() {
 	if c.token == "" || c.gameID == "" {
 		fmt.Println("Please login and join a game first")
@@ -339,8 +407,7 @@ func (c *TestClient) connectWebSocket() {
 				break
 			}
 			
-			ping := map[string]string{"type": "ping"}
-			c.wsConn.WriteJSON(ping)
+			c.sendRequest(map[string]string{"type": protocol.MsgTypePing})
 		}
 	}()
 }
\ No newline at end of file
@@ -10,13 +10,17 @@ import (
 
 func main() {
 	// Load configuration
-	cfg, err := config.Load("config/game_config.json")
+	configPath := "config/game_config.json"
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Create and start server
-	srv := server.New(cfg)
+	srv, err := server.New(cfg, configPath)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
 	log.Printf("Starting TCR Game Server on port %s", cfg.Server.Port)
 	
 	if err := srv.Start(cfg.Server.Port); err != nil {
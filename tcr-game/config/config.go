@@ -3,6 +3,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 )
 
@@ -10,6 +11,8 @@ type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Game     GameConfig     `json:"game"`
 	Database DatabaseConfig `json:"database"`
+	Matchmaking MatchmakingConfig `json:"matchmaking"`
+	Rating   RatingConfig   `json:"rating"`
 }
 
 type ServerConfig struct {
@@ -17,16 +20,50 @@ type ServerConfig struct {
 	ReadTimeout     int    `json:"read_timeout"`
 	WriteTimeout    int    `json:"write_timeout"`
 	MaxConnections  int    `json:"max_connections"`
+	BalancePoll     int    `json:"balance_poll_ms"` // how often balance files are checked for edits, 0 disables reloading
+	WebSocket       WebSocketConfig `json:"websocket"`
+	Admins          []string `json:"admins"` // usernames allowed to use the /api/admin endpoints
+	GameRetention   int      `json:"game_retention_seconds"` // how long a finished game stays loaded, 0 takes the server's default
+}
+
+// Slow consumer policies
+const (
+	SlowConsumerDrop       = "drop"
+	SlowConsumerDisconnect = "disconnect"
+)
+
+// WebSocketConfig sets how messages are written to each WebSocket. A
+// connection's messages wait in a queue of QueueSize for its writer; a
+// client whose queue is full is a slow consumer, and SlowConsumer decides
+// whether the message is dropped or the client disconnected. The server
+// pings every PingInterval and disconnects clients that do not answer
+// within PongTimeout. The last ResumeBuffer events of each game are kept
+// for clients reconnecting with ?since=. Zero values take the server's
+// defaults.
+type WebSocketConfig struct {
+	QueueSize    int    `json:"queue_size"`
+	WriteTimeout int    `json:"write_timeout_ms"`
+	PingInterval int    `json:"ping_interval_ms"`
+	PongTimeout  int    `json:"pong_timeout_ms"`
+	SlowConsumer string `json:"slow_consumer"` // "drop" or "disconnect"
+	ResumeBuffer int    `json:"resume_buffer"`
 }
 
 type GameConfig struct {
 	Simple   SimpleGameConfig   `json:"simple"`
 	Enhanced EnhancedGameConfig `json:"enhanced"`
+	Draft    DraftGameConfig    `json:"draft"`
+	Team     TeamGameConfig     `json:"team"`
+	FFA      FFAGameConfig      `json:"ffa"`
+	DisconnectGrace int         `json:"disconnect_grace_seconds"` // how long a disconnected player has to return before forfeiting, 0 never forfeits
 }
 
 type SimpleGameConfig struct {
 	MaxPlayers int `json:"max_players"`
 	TurnTime   int `json:"turn_time_seconds"`
+	MaxTimeouts int `json:"max_consecutive_timeouts"` // timed out turns in a row before a player forfeits, 0 never forfeits
+	ExpWin     int `json:"exp_win"`
+	ExpDraw    int `json:"exp_draw"`
 }
 
 type EnhancedGameConfig struct {
@@ -37,12 +74,64 @@ type EnhancedGameConfig struct {
 	CritMultiplier float64 `json:"crit_multiplier"`
 	ExpWin        int     `json:"exp_win"`
 	ExpDraw       int     `json:"exp_draw"`
+	TickInterval  int     `json:"tick_interval_ms"`
+	OvertimeDuration    int     `json:"overtime_seconds"`         // 0 skips overtime
+	OvertimeManaFactor  float64 `json:"overtime_mana_multiplier"` // mana regenerates this much faster in overtime and sudden death
+	SuddenDeathDuration int     `json:"sudden_death_seconds"`     // 0 skips sudden death
+}
+
+// DraftGameConfig sets the pick and ban phase of draft mode. The match
+// after the draft is played under the simple mode settings.
+type DraftGameConfig struct {
+	BansPerPlayer  int `json:"bans_per_player"`
+	PicksPerPlayer int `json:"picks_per_player"` // 0 picks 3 troops each
+	PickTime       int `json:"pick_time_seconds"` // 0 leaves picks untimed
+	ExpWin         int `json:"exp_win"`
+	ExpDraw        int `json:"exp_draw"`
+}
+
+// TeamGameConfig sets 2v2 team matches, which are played in turns under
+// the simple mode rules
+type TeamGameConfig struct {
+	TurnTime int `json:"turn_time_seconds"` // 0 uses simple.turn_time_seconds
+	ExpWin   int `json:"exp_win"`
+	ExpDraw  int `json:"exp_draw"`
+}
+
+// FFAGameConfig sets free-for-all matches of 3 or 4 players, played in
+// turns under the simple mode rules. Experience goes to the player who
+// finishes first, or is shared as a draw by players tied for first.
+type FFAGameConfig struct {
+	TurnTime int `json:"turn_time_seconds"` // 0 uses simple.turn_time_seconds
+	ExpWin   int `json:"exp_win"`
+	ExpDraw  int `json:"exp_draw"`
+}
+
+// MatchmakingConfig controls how far apart two queued players may be. The
+// window starts at InitialWindow and widens by WindowGrowth every second a
+// player waits, up to MaxWindow.
+type MatchmakingConfig struct {
+	InitialWindow int `json:"initial_window"`
+	WindowGrowth  int `json:"window_growth_per_second"`
+	MaxWindow     int `json:"max_window"`
+	Interval      int `json:"interval_ms"`
+}
+
+// RatingConfig sets the Elo K-factor. Players in their first
+// ProvisionalGames games of a mode use the larger ProvisionalKFactor so
+// their rating settles quickly.
+type RatingConfig struct {
+	KFactor            int `json:"k_factor"`
+	ProvisionalKFactor int `json:"provisional_k_factor"`
+	ProvisionalGames   int `json:"provisional_games"`
 }
 
 type DatabaseConfig struct {
 	TroopsFile  string `json:"troops_file"`
 	TowersFile  string `json:"towers_file"`
+	UpgradesFile string `json:"upgrades_file"`
 	PlayersDir  string `json:"players_directory"`
+	GamesDir    string `json:"games_directory"`
 }
 
 func Load(path string) (*Config, error) {
@@ -58,4 +147,74 @@ func Load(path string) (*Config, error) {
 	}
 
 	return &config, nil
-}
\ No newline at end of file
+}
+// Validate checks the WebSocket settings
+func (w *WebSocketConfig) Validate() error {
+	if w.QueueSize < 0 || w.WriteTimeout < 0 || w.PingInterval < 0 || w.PongTimeout < 0 || w.ResumeBuffer < 0 {
+		return errors.New("websocket queue sizes and timeouts must not be negative")
+	}
+	if w.PingInterval > 0 && w.PongTimeout > 0 && w.PingInterval >= w.PongTimeout {
+		return errors.New("websocket.ping_interval_ms must be shorter than pong_timeout_ms")
+	}
+	switch w.SlowConsumer {
+	case "", SlowConsumerDrop, SlowConsumerDisconnect:
+		return nil
+	}
+	return errors.New("websocket.slow_consumer must be \"drop\" or \"disconnect\"")
+}
+
+// Validate checks the balance values of the game section
+func (g *GameConfig) Validate() error {
+	if g.Simple.MaxPlayers < 2 {
+		return errors.New("simple.max_players must be at least 2")
+	}
+	if g.Simple.TurnTime <= 0 {
+		return errors.New("simple.turn_time_seconds must be positive")
+	}
+	if g.Simple.MaxTimeouts < 0 {
+		return errors.New("simple.max_consecutive_timeouts must not be negative")
+	}
+	if g.Enhanced.GameDuration <= 0 {
+		return errors.New("enhanced.game_duration_seconds must be positive")
+	}
+	if g.Enhanced.ManaRegen < 0 {
+		return errors.New("enhanced.mana_regen_per_second must not be negative")
+	}
+	if g.Enhanced.CritMultiplier < 1 {
+		return errors.New("enhanced.crit_multiplier must be at least 1")
+	}
+	if g.Simple.ExpWin < 0 || g.Simple.ExpDraw < 0 || g.Enhanced.ExpWin < 0 || g.Enhanced.ExpDraw < 0 {
+		return errors.New("enhanced experience rewards must not be negative")
+	}
+	if g.Enhanced.TickInterval < 0 {
+		return errors.New("enhanced.tick_interval_ms must not be negative")
+	}
+	if g.Enhanced.OvertimeDuration < 0 || g.Enhanced.SuddenDeathDuration < 0 {
+		return errors.New("enhanced overtime and sudden death must not be negative")
+	}
+	if g.Enhanced.OvertimeDuration > 0 && g.Enhanced.OvertimeManaFactor < 1 {
+		return errors.New("enhanced.overtime_mana_multiplier must be at least 1")
+	}
+	if g.Draft.BansPerPlayer < 0 || g.Draft.PicksPerPlayer < 0 || g.Draft.PickTime < 0 {
+		return errors.New("draft bans, picks and pick time must not be negative")
+	}
+	if g.Draft.ExpWin < 0 || g.Draft.ExpDraw < 0 {
+		return errors.New("draft experience rewards must not be negative")
+	}
+	if g.Team.TurnTime < 0 {
+		return errors.New("team.turn_time_seconds must not be negative")
+	}
+	if g.Team.ExpWin < 0 || g.Team.ExpDraw < 0 {
+		return errors.New("team experience rewards must not be negative")
+	}
+	if g.FFA.TurnTime < 0 {
+		return errors.New("ffa.turn_time_seconds must not be negative")
+	}
+	if g.FFA.ExpWin < 0 || g.FFA.ExpDraw < 0 {
+		return errors.New("ffa experience rewards must not be negative")
+	}
+	if g.DisconnectGrace < 0 {
+		return errors.New("disconnect_grace_seconds must not be negative")
+	}
+	return nil
+}
@@ -2,67 +2,36 @@
 package auth
 
 import (
+	"errors"
+	
+	"tcr-game/internal/game"
 	"tcr-game/internal/models"
 	"tcr-game/internal/storage"
 )
 
 type UserManager struct {
 	storage *storage.JSONStorage
+	engine  *game.GameEngine
 }
 
-func NewUserManager(storage *storage.JSONStorage) *UserManager {
+func NewUserManager(storage *storage.JSONStorage, engine *game.GameEngine) *UserManager {
 	return &UserManager{
 		storage: storage,
+		engine:  engine,
 	}
 }
 
-func (um *UserManager) UpdatePlayerStats(player *models.Player, won bool, drawn bool) error {
-	player.Stats.GamesPlayed++
-	
-	if won {
-		player.Stats.GamesWon++
-	} else if drawn {
-		player.Stats.GamesDrawn++
-	} else {
-		player.Stats.GamesLost++
-	}
-	
-	return um.storage.SavePlayer(player)
-}
-
 func (um *UserManager) GetPlayerProfile(playerID string) (*models.Player, error) {
 	return um.storage.LoadPlayer(playerID)
 }
 
-func (um *UserManager) UpdatePlayerExperience(player *models.Player, exp int) error {
-	oldLevel := player.Level
-	player.AddExperience(exp)
-	
-	// Check if player leveled up
-	if player.Level > oldLevel {
-		// When player levels up, they can upgrade a troop or tower
-		// This is just a placeholder - you might want to implement
-		// a system where players choose what to upgrade
-	}
-	
-	return um.storage.SavePlayer(player)
-}
-
+// LoadAvailableTroops deals the player's active deck, at the player's troop
+// levels, from the troop catalogue of the balance new games use
 func (um *UserManager) LoadAvailableTroops(player *models.Player) error {
-	// Load all troop templates
-	troops, err := um.storage.LoadTroops()
-	if err != nil {
-		return err
-	}
-	
-	// Apply player's troop levels
-	player.AvailableTroops = make([]*models.Troop, len(troops))
-	for i, troop := range troops {
-		// Create a copy of the troop
-		playerTroop := *troop
-		playerTroop.ApplyLevel(player.GetTroopLevel(troop.ID))
-		player.AvailableTroops[i] = &playerTroop
+	player.AvailableTroops = player.DeckTroops(um.engine.Balance().Troops)
+	if len(player.AvailableTroops) == 0 {
+		return errors.New("no troops available")
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
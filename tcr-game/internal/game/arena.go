@@ -0,0 +1,388 @@
+// internal/game/arena.go - Lane-based real-time troop simulation for enhanced mode
+package game
+
+import (
+	"fmt"
+
+	"tcr-game/internal/models"
+)
+
+const (
+	LeftLane  = 0
+	RightLane = 1
+)
+
+// ArenaConfig describes the arena geometry. Positions run from 0 (the first
+// player's king) to LaneLength (the second player's king).
+type ArenaConfig struct {
+	LaneLength  int `json:"lane_length"`
+	GuardOffset int `json:"guard_offset"` // distance from a king to its guard towers
+	TroopSpeed  int `json:"troop_speed"`  // distance covered per tick
+	AttackRange int `json:"attack_range"`
+	TowerRange  int `json:"tower_range"`
+}
+
+func DefaultArenaConfig() ArenaConfig {
+	return ArenaConfig{
+		LaneLength:  100,
+		GuardOffset: 10,
+		TroopSpeed:  5,
+		AttackRange: 5,
+		TowerRange:  10,
+	}
+}
+
+// ArenaUnit is a spawned troop walking a lane
+type ArenaUnit struct {
+	ID        string
+	OwnerID   string
+	Lane      int
+	Position  int
+	Direction int // +1 towards the second player's king, -1 towards the first
+	Troop     *models.Troop
+}
+
+// ArenaUnitState is a serializable snapshot of an ArenaUnit
+type ArenaUnitState struct {
+	ID       string `json:"id"`
+	OwnerID  string `json:"owner_id"`
+	TroopID  string `json:"troop_id"`
+	Lane     int    `json:"lane"`
+	Position int    `json:"position"`
+	HP       int    `json:"hp"`
+	MaxHP    int    `json:"max_hp"`
+}
+
+// ArenaHit is one attack resolved during a tick
+type ArenaHit struct {
+	AttackerUnit string `json:"attacker_unit"`
+	AttackerID   string `json:"attacker_id"`
+	DefenderID   string `json:"defender_id"`
+	TargetUnit   string `json:"target_unit,omitempty"`
+	TargetTower  int    `json:"target_tower"` // -1 when a troop was hit
+	Damage       int    `json:"damage"`
+	CriticalHit  bool   `json:"critical_hit"`
+	Destroyed    bool   `json:"destroyed"`
+}
+
+// ArenaTick is the outcome of a single simulation step
+type ArenaTick struct {
+	Tick      int              `json:"tick"`
+	Units     []ArenaUnitState `json:"units"`
+	Hits      []ArenaHit       `json:"hits"`
+	Defense   []DefenseHit     `json:"defense"`
+	GameEnded bool             `json:"game_ended"`
+	Winner    string           `json:"winner,omitempty"`
+}
+
+type Arena struct {
+	game         *models.Game
+	battleEngine *BattleEngine
+	config       ArenaConfig
+	units        []*ArenaUnit
+	tick         int
+	nextUnitID   int
+}
+
+func NewArena(game *models.Game, battleEngine *BattleEngine, config ArenaConfig) *Arena {
+	return &Arena{
+		game:         game,
+		battleEngine: battleEngine,
+		config:       config,
+		units:        make([]*ArenaUnit, 0),
+	}
+}
+
+// Tick returns the number of steps simulated so far
+func (a *Arena) Tick() int {
+	return a.tick
+}
+
+// LaneForTarget picks the lane a troop aimed at the given tower walks down.
+// Towers other than the king, such as guard towers, sit on their own lane;
+// the king is reached through whichever lane has already been opened.
+func (a *Arena) LaneForTarget(defender *models.Player, targetTower int) (int, error) {
+	if targetTower < 0 || targetTower >= len(defender.Towers) {
+		return 0, fmt.Errorf("invalid tower index: %d", targetTower)
+	}
+
+	tower := defender.Towers[targetTower]
+	if tower.Type != models.KingTower {
+		return tower.Position, nil
+	}
+
+	for lane := 0; lane < models.ArenaLanes; lane++ {
+		if guard := a.guardTower(defender, lane); guard == nil || !guard.IsAlive() {
+			return lane, nil
+		}
+	}
+	return LeftLane, nil
+}
+
+// Spawn places a copy of the troop at its owner's guard line on the given lane
+func (a *Arena) Spawn(owner *models.Player, template *models.Troop, lane int) (*ArenaUnit, error) {
+	if lane < 0 || lane >= models.ArenaLanes {
+		return nil, fmt.Errorf("invalid lane: %d", lane)
+	}
+
+	troop := *template
+	a.nextUnitID++
+	unit := &ArenaUnit{
+		ID:        fmt.Sprintf("u%d", a.nextUnitID),
+		OwnerID:   owner.ID,
+		Lane:      lane,
+		Position:  a.guardPosition(owner.ID),
+		Direction: a.direction(owner.ID),
+		Troop:     &troop,
+	}
+	a.units = append(a.units, unit)
+
+	return unit, nil
+}
+
+// Step advances the simulation by one tick. Units act in spawn order: each
+// attacks the nearest enemy troop in range, otherwise the enemy tower guarding
+// its lane if in range, otherwise walks towards that tower. Living towers
+// then fire at the nearest enemy troop within their range.
+func (a *Arena) Step() *ArenaTick {
+	a.tick++
+	result := &ArenaTick{
+		Tick:    a.tick,
+		Hits:    make([]ArenaHit, 0),
+		Defense: make([]DefenseHit, 0),
+	}
+
+	for _, unit := range a.units {
+		if !unit.Troop.IsAlive() {
+			continue
+		}
+
+		defender := a.game.GetOpponent(unit.OwnerID)
+		if defender == nil {
+			continue
+		}
+
+		if enemy := a.nearestEnemy(unit); enemy != nil {
+			damage, critical := a.battleEngine.CalculateDamage(unit.Troop, enemy.Troop, true, a.game.RNG())
+			enemy.Troop.TakeDamage(damage)
+			result.Hits = append(result.Hits, ArenaHit{
+				AttackerUnit: unit.ID,
+				AttackerID:   unit.OwnerID,
+				DefenderID:   enemy.OwnerID,
+				TargetUnit:   enemy.ID,
+				TargetTower:  -1,
+				Damage:       damage,
+				CriticalHit:  critical,
+				Destroyed:    !enemy.Troop.IsAlive(),
+			})
+			continue
+		}
+
+		towerIndex, towerPosition := a.laneTarget(defender, unit.Lane)
+		if towerIndex < 0 {
+			continue
+		}
+
+		if a.distance(unit.Position, towerPosition) <= a.config.AttackRange {
+			tower := defender.Towers[towerIndex]
+			damage, critical := a.battleEngine.CalculateDamage(unit.Troop, tower, true, a.game.RNG())
+			tower.TakeDamage(damage)
+
+			hit := ArenaHit{
+				AttackerUnit: unit.ID,
+				AttackerID:   unit.OwnerID,
+				DefenderID:   defender.ID,
+				TargetTower:  towerIndex,
+				Damage:       damage,
+				CriticalHit:  critical,
+				Destroyed:    !tower.IsAlive(),
+			}
+			result.Hits = append(result.Hits, hit)
+
+			if hit.Destroyed {
+				result.GameEnded, result.Winner = a.battleEngine.checkGameEndConditions(a.game, defender)
+				if result.GameEnded {
+					break
+				}
+			}
+			continue
+		}
+
+		unit.Position += unit.Direction * a.speed(unit)
+		if (towerPosition-unit.Position)*unit.Direction < 0 {
+			unit.Position = towerPosition
+		}
+	}
+
+	if !result.GameEnded {
+		result.Defense = a.defend()
+	}
+
+	a.removeDeadUnits()
+	result.Units = a.Units()
+
+	return result
+}
+
+// speed is the distance a unit covers per tick, falling back to the
+// arena default for troops without a speed of their own
+func (a *Arena) speed(unit *ArenaUnit) int {
+	if unit.Troop.Speed > 0 {
+		return unit.Troop.Speed
+	}
+	return a.config.TroopSpeed
+}
+
+// defend runs the defence phase for every living tower in player and index order
+func (a *Arena) defend() []DefenseHit {
+	hits := make([]DefenseHit, 0)
+	for _, owner := range a.game.Players {
+		for i, tower := range owner.Towers {
+			if !tower.IsAlive() {
+				continue
+			}
+			target := a.nearestAttacker(owner, tower)
+			if target == nil {
+				continue
+			}
+			hit := a.battleEngine.TowerStrike(a.game, owner, i, target.Troop)
+			hit.TargetUnit = target.ID
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+// nearestAttacker finds the closest enemy unit a tower can reach. Lane towers
+// cover their own lane; the king covers every lane.
+func (a *Arena) nearestAttacker(owner *models.Player, tower *models.Tower) *ArenaUnit {
+	position := a.kingPosition(owner.ID)
+	if tower.Type != models.KingTower {
+		position = a.guardPosition(owner.ID)
+	}
+
+	var nearest *ArenaUnit
+	for _, unit := range a.units {
+		if unit.OwnerID == owner.ID || !unit.Troop.IsAlive() {
+			continue
+		}
+		if tower.Type != models.KingTower && unit.Lane != tower.Position {
+			continue
+		}
+		distance := a.distance(position, unit.Position)
+		if distance > a.config.TowerRange {
+			continue
+		}
+		if nearest == nil || distance < a.distance(position, nearest.Position) {
+			nearest = unit
+		}
+	}
+	return nearest
+}
+
+// Units returns a snapshot of the living units
+func (a *Arena) Units() []ArenaUnitState {
+	states := make([]ArenaUnitState, 0, len(a.units))
+	for _, unit := range a.units {
+		if unit.Troop.IsAlive() {
+			states = append(states, unit.State())
+		}
+	}
+	return states
+}
+
+func (u *ArenaUnit) State() ArenaUnitState {
+	return ArenaUnitState{
+		ID:       u.ID,
+		OwnerID:  u.OwnerID,
+		TroopID:  u.Troop.ID,
+		Lane:     u.Lane,
+		Position: u.Position,
+		HP:       u.Troop.HP,
+		MaxHP:    u.Troop.MaxHP,
+	}
+}
+
+func (a *Arena) nearestEnemy(unit *ArenaUnit) *ArenaUnit {
+	var nearest *ArenaUnit
+	for _, other := range a.units {
+		if other.OwnerID == unit.OwnerID || other.Lane != unit.Lane || !other.Troop.IsAlive() {
+			continue
+		}
+		distance := a.distance(unit.Position, other.Position)
+		if distance > a.config.AttackRange {
+			continue
+		}
+		if nearest == nil || distance < a.distance(unit.Position, nearest.Position) {
+			nearest = other
+		}
+	}
+	return nearest
+}
+
+// laneTarget returns the index and position of the defender's tower a unit on
+// the lane is heading for, or -1 if the defender has no tower left to attack
+func (a *Arena) laneTarget(defender *models.Player, lane int) (int, int) {
+	for i, tower := range defender.Towers {
+		if tower.Type != models.KingTower && tower.Position == lane && tower.IsAlive() {
+			return i, a.guardPosition(defender.ID)
+		}
+	}
+	for i, tower := range defender.Towers {
+		if tower.Type == models.KingTower && tower.IsAlive() {
+			return i, a.kingPosition(defender.ID)
+		}
+	}
+	return -1, 0
+}
+
+func (a *Arena) guardTower(player *models.Player, lane int) *models.Tower {
+	for _, tower := range player.Towers {
+		if tower.Type != models.KingTower && tower.Position == lane {
+			return tower
+		}
+	}
+	return nil
+}
+
+func (a *Arena) removeDeadUnits() {
+	alive := a.units[:0]
+	for _, unit := range a.units {
+		if unit.Troop.IsAlive() {
+			alive = append(alive, unit)
+		}
+	}
+	a.units = alive
+}
+
+func (a *Arena) isFirstPlayer(playerID string) bool {
+	return len(a.game.Players) > 0 && a.game.Players[0].ID == playerID
+}
+
+func (a *Arena) direction(playerID string) int {
+	if a.isFirstPlayer(playerID) {
+		return 1
+	}
+	return -1
+}
+
+func (a *Arena) kingPosition(playerID string) int {
+	if a.isFirstPlayer(playerID) {
+		return 0
+	}
+	return a.config.LaneLength
+}
+
+func (a *Arena) guardPosition(playerID string) int {
+	if a.isFirstPlayer(playerID) {
+		return a.config.GuardOffset
+	}
+	return a.config.LaneLength - a.config.GuardOffset
+}
+
+func (a *Arena) distance(from, to int) int {
+	if from > to {
+		return from - to
+	}
+	return to - from
+}
@@ -0,0 +1,143 @@
+// internal/game/balance.go - Versioned, hot-reloadable game balance data
+package game
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// Balance is one validated set of troop, tower and rule values. A game
+// keeps the balance it was created with even if a newer one is loaded.
+type Balance struct {
+	Version  int                      `json:"version"`
+	LoadedAt time.Time                `json:"loaded_at"`
+	Game     config.GameConfig        `json:"game"`
+	Troops   *models.TroopCatalogue   `json:"-"`
+	Towers   *models.TowerCatalogue   `json:"-"`
+	Upgrades *models.UpgradeCatalogue `json:"upgrades"`
+}
+
+// BalanceLoader reads the balance files and numbers each successful load
+type BalanceLoader struct {
+	configPath string
+	storage    *storage.JSONStorage
+	version    int
+	mutex      sync.Mutex
+}
+
+func NewBalanceLoader(configPath string, storage *storage.JSONStorage) *BalanceLoader {
+	return &BalanceLoader{
+		configPath: configPath,
+		storage:    storage,
+	}
+}
+
+// Files lists the files a balance is built from
+func (bl *BalanceLoader) Files() []string {
+	return []string{bl.configPath, bl.storage.TroopsFile(), bl.storage.TowersFile(), bl.storage.UpgradesFile()}
+}
+
+// Load reads and validates every balance file. The version only advances
+// when all of them are valid, so a bad edit never produces a new version.
+func (bl *BalanceLoader) Load() (*Balance, error) {
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	cfg, err := config.Load(bl.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	if err := cfg.Game.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid game config: %v", err)
+	}
+
+	troops, err := bl.storage.LoadTroops()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load troops: %v", err)
+	}
+
+	towers, err := bl.storage.LoadTowers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load towers: %v", err)
+	}
+
+	upgrades, err := bl.storage.LoadUpgrades()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upgrades: %v", err)
+	}
+
+	bl.version++
+	return &Balance{
+		Version:  bl.version,
+		LoadedAt: time.Now(),
+		Game:     cfg.Game,
+		Troops:   troops,
+		Towers:   towers,
+		Upgrades: upgrades,
+	}, nil
+}
+
+// Watch polls the balance files and calls onLoad with every new valid
+// balance. Invalid edits are logged and the previous balance stays active.
+// Closing the returned channel stops the watcher.
+func (bl *BalanceLoader) Watch(interval time.Duration, onLoad func(*Balance)) chan struct{} {
+	stop := make(chan struct{})
+	modTimes := bl.modTimes()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				current := bl.modTimes()
+				if !changed(modTimes, current) {
+					continue
+				}
+				modTimes = current
+
+				balance, err := bl.Load()
+				if err != nil {
+					log.Printf("Ignoring balance change: %v", err)
+					continue
+				}
+				log.Printf("Loaded balance version %d", balance.Version)
+				onLoad(balance)
+			}
+		}
+	}()
+
+	return stop
+}
+
+func (bl *BalanceLoader) modTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, path := range bl.Files() {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func changed(before, after map[string]time.Time) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for path, modTime := range after {
+		if !before[path].Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,8 +2,8 @@
 package game
 
 import (
+	"errors"
 	"fmt"
-	"math/rand"
 	
 	"tcr-game/internal/models"
 )
@@ -11,6 +11,8 @@ import (
 type BattleResult struct {
 	AttackerID    string `json:"attacker_id"`
 	DefenderID    string `json:"defender_id"`
+	AttackerTeam  int    `json:"attacker_team"`
+	DefenderTeam  int    `json:"defender_team"`
 	TroopUsed     string `json:"troop_used"`
 	TargetTower   int    `json:"target_tower"`
 	Damage        int    `json:"damage"`
@@ -19,6 +21,20 @@ type BattleResult struct {
 	CanContinue   bool   `json:"can_continue"`
 	GameEnded     bool   `json:"game_ended"`
 	Winner        string `json:"winner,omitempty"`
+	DefenseHits   []DefenseHit `json:"defense_hits,omitempty"`
+	TroopDestroyed bool  `json:"troop_destroyed"`
+	Eliminated    []string `json:"eliminated,omitempty"` // players knocked out by the attack
+}
+
+// DefenseHit is a tower firing back at an attacking troop
+type DefenseHit struct {
+	DefenderID  string `json:"defender_id"`
+	TowerIndex  int    `json:"tower_index"`
+	TroopID     string `json:"troop_id"`
+	TargetUnit  string `json:"target_unit,omitempty"` // arena unit hit in enhanced mode
+	Damage      int    `json:"damage"`
+	CriticalHit bool   `json:"critical_hit"`
+	TroopKilled bool   `json:"troop_killed"`
 }
 
 type BattleEngine struct {
@@ -32,18 +48,27 @@ func NewBattleEngine(critMultiplier float64) *BattleEngine {
 }
 
 // CalculateDamage implements the damage formula: DMG = ATK_A - DEF_B (if ≥ 0)
-// With critical hit chance for enhanced mode
-func (be *BattleEngine) CalculateDamage(attacker *models.Troop, defender models.Attackable, enhancedMode bool) (int, bool) {
-	baseDamage := attacker.Attack - defender.GetDefense()
+// With critical hit chance in modes that allow them, rolled on the game's random source
+func (be *BattleEngine) CalculateDamage(attacker *models.Troop, defender models.Attackable, criticalHits bool, rng models.RandomSource) (int, bool) {
+	return be.rollDamage(attacker.Attack, attacker.CritChance, defender, criticalHits, rng)
+}
+
+// CalculateTowerDamage applies the same formula to a tower firing at a troop
+func (be *BattleEngine) CalculateTowerDamage(tower *models.Tower, defender models.Attackable, criticalHits bool, rng models.RandomSource) (int, bool) {
+	return be.rollDamage(tower.Attack, tower.CritChance, defender, criticalHits, rng)
+}
+
+func (be *BattleEngine) rollDamage(attack int, critChance float64, defender models.Attackable, criticalHits bool, rng models.RandomSource) (int, bool) {
+	baseDamage := attack - defender.GetDefense()
 	if baseDamage < 0 {
 		baseDamage = 0
 	}
 	
-	// Check for critical hit in enhanced mode
+	// Check for critical hit
 	criticalHit := false
 	damage := baseDamage
 	
-	if enhancedMode && rand.Float64() < attacker.CritChance {
+	if criticalHits && rng.Float64() < critChance {
 		criticalHit = true
 		damage = int(float64(baseDamage) * be.critMultiplier)
 	}
@@ -51,22 +76,46 @@ func (be *BattleEngine) CalculateDamage(attacker *models.Troop, defender models.
 	return damage, criticalHit
 }
 
-// ExecuteAttack performs a single attack from troop to target tower
-func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopID string, targetTowerIndex int) (*BattleResult, error) {
+// TowerStrike is the defence phase: a living tower fires at an enemy troop
+func (be *BattleEngine) TowerStrike(game *models.Game, owner *models.Player, towerIndex int, troop *models.Troop) DefenseHit {
+	tower := owner.Towers[towerIndex]
+	damage, criticalHit := be.CalculateTowerDamage(tower, troop, attackRules(game.Mode).CriticalHits, game.RNG())
+	troop.TakeDamage(damage)
+	
+	return DefenseHit{
+		DefenderID:  owner.ID,
+		TowerIndex:  towerIndex,
+		TroopID:     troop.ID,
+		Damage:      damage,
+		CriticalHit: criticalHit,
+		TroopKilled: !troop.IsAlive(),
+	}
+}
+
+// ExecuteAttack performs a single attack from troop to a tower of the
+// target player under the attack rules of the game's mode. The target may
+// be left empty while the attacker has a single opponent.
+func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID, targetPlayerID string, troopID string, targetTowerIndex int) (*BattleResult, error) {
+	rules := attackRules(game.Mode)
+	
 	// Find attacker and defender
-	var attacker, defender *models.Player
+	var attacker *models.Player
 	for _, player := range game.Players {
 		if player.ID == playerID {
 			attacker = player
-		} else {
-			defender = player
+			break
 		}
 	}
 	
-	if attacker == nil || defender == nil {
+	if attacker == nil {
 		return nil, fmt.Errorf("invalid players")
 	}
 	
+	defender, err := targetOpponent(game, playerID, targetPlayerID)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Find the troop being used
 	var troopUsed *models.Troop
 	for _, troop := range attacker.AvailableTroops {
@@ -80,20 +129,24 @@ func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopI
 		return nil, fmt.Errorf("troop not found: %s", troopID)
 	}
 	
+	if !troopUsed.IsAlive() {
+		return nil, fmt.Errorf("troop has been destroyed: %s", troopID)
+	}
+	
 	// Check if target tower index is valid
 	if targetTowerIndex < 0 || targetTowerIndex >= len(defender.Towers) {
 		return nil, fmt.Errorf("invalid tower index: %d", targetTowerIndex)
 	}
 	
-	// Validate attack rules (Simple mode)
-	if game.Mode == models.SimpleMode {
-		if err := be.validateSimpleAttackRules(defender, targetTowerIndex); err != nil {
+	// Validate tower order
+	if rules.OrderedTargets {
+		if err := be.validateTowerOrder(defender, targetTowerIndex); err != nil {
 			return nil, err
 		}
 	}
 	
-	// Check mana cost (Enhanced mode)
-	if game.Mode == models.EnhancedMode {
+	// Check mana cost
+	if rules.SpendMana {
 		if !attacker.CanSpendMana(troopUsed.ManaCost) {
 			return nil, fmt.Errorf("insufficient mana: need %d, have %d", troopUsed.ManaCost, attacker.Mana)
 		}
@@ -107,8 +160,7 @@ func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopI
 	}
 	
 	// Calculate damage
-	enhancedMode := game.Mode == models.EnhancedMode
-	damage, criticalHit := be.CalculateDamage(troopUsed, targetTower, enhancedMode)
+	damage, criticalHit := be.CalculateDamage(troopUsed, targetTower, rules.CriticalHits, game.RNG())
 	
 	// Apply damage
 	targetTower.TakeDamage(damage)
@@ -117,6 +169,8 @@ func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopI
 	result := &BattleResult{
 		AttackerID:     attacker.ID,
 		DefenderID:     defender.ID,
+		AttackerTeam:   game.TeamOf(attacker.ID),
+		DefenderTeam:   game.TeamOf(defender.ID),
 		TroopUsed:     troopID,
 		TargetTower:   targetTowerIndex,
 		Damage:        damage,
@@ -128,13 +182,23 @@ func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopI
 	
 	// Check if tower was destroyed
 	if result.TowerDestroyed {
-		// In simple mode, if a tower is destroyed, the troop can continue attacking
-		if game.Mode == models.SimpleMode {
-			result.CanContinue = true
-		}
+		// In some modes, if a tower is destroyed, the troop can continue attacking
+		result.CanContinue = rules.ContinueOnKill
 		
 		// Check for game end conditions
 		result.GameEnded, result.Winner = be.checkGameEndConditions(game, defender)
+		if !game.TeamStanding(result.DefenderTeam) {
+			for _, player := range game.TeamMembers(result.DefenderTeam) {
+				result.Eliminated = append(result.Eliminated, player.ID)
+			}
+		}
+	}
+	
+	// The attacked tower may fire back if it is still standing
+	if rules.Retaliation && targetTower.IsAlive() {
+		hit := be.TowerStrike(game, defender, targetTowerIndex, troopUsed)
+		result.DefenseHits = append(result.DefenseHits, hit)
+		result.TroopDestroyed = hit.TroopKilled
 	}
 	
 	// Add battle event to game
@@ -143,19 +207,53 @@ func (be *BattleEngine) ExecuteAttack(game *models.Game, playerID string, troopI
 	return result, nil
 }
 
-// validateSimpleAttackRules validates attack rules for simple mode
-func (be *BattleEngine) validateSimpleAttackRules(defender *models.Player, targetTowerIndex int) error {
-	// Rule: Must destroy guard towers before attacking king tower
-	if targetTowerIndex == 2 { // King tower
-		// Check if both guard towers are destroyed
-		if defender.Towers[0].IsAlive() || defender.Towers[1].IsAlive() {
-			return fmt.Errorf("must destroy guard towers before attacking king tower")
+// targetOpponent resolves the player an action targets. Without a target
+// the attacker's only opponent left standing is chosen; teammates and
+// eliminated players cannot be targeted.
+func targetOpponent(game *models.Game, playerID, targetPlayerID string) (*models.Player, error) {
+	opponents := standingOpponents(game, playerID)
+	if targetPlayerID == "" {
+		if len(opponents) != 1 {
+			return nil, errors.New("target_player is required")
 		}
+		return opponents[0], nil
 	}
 	
-	// Rule: Must destroy first guard tower before second
-	if targetTowerIndex == 1 && defender.Towers[0].IsAlive() {
-		return fmt.Errorf("must destroy left guard tower before right guard tower")
+	for _, opponent := range opponents {
+		if opponent.ID == targetPlayerID {
+			return opponent, nil
+		}
+	}
+	for _, opponent := range game.Opponents(playerID) {
+		if opponent.ID == targetPlayerID {
+			return nil, fmt.Errorf("player has been eliminated: %s", targetPlayerID)
+		}
+	}
+	return nil, fmt.Errorf("invalid target player: %s", targetPlayerID)
+}
+
+// standingOpponents returns the opponents of a player still in the game
+func standingOpponents(game *models.Game, playerID string) []*models.Player {
+	opponents := make([]*models.Player, 0)
+	for _, opponent := range game.Opponents(playerID) {
+		if game.TeamStanding(game.TeamOf(opponent.ID)) {
+			opponents = append(opponents, opponent)
+		}
+	}
+	return opponents
+}
+
+// validateTowerOrder checks the target is the first standing tower
+func (be *BattleEngine) validateTowerOrder(defender *models.Player, targetTowerIndex int) error {
+	// Rule: Towers must be destroyed in layout order, so guards fall before the king
+	for i, tower := range defender.Towers {
+		if !tower.IsAlive() {
+			continue
+		}
+		if i != targetTowerIndex {
+			return fmt.Errorf("must destroy %s before attacking %s", tower.Name, defender.Towers[targetTowerIndex].Name)
+		}
+		break
 	}
 	
 	return nil
@@ -163,22 +261,38 @@ func (be *BattleEngine) validateSimpleAttackRules(defender *models.Player, targe
 
 // checkGameEndConditions checks if the game has ended
 func (be *BattleEngine) checkGameEndConditions(game *models.Game, defender *models.Player) (bool, string) {
-	// Check if king tower is destroyed
-	if !defender.Towers[2].IsAlive() {
-		// Find the winner (the other player)
-		for _, player := range game.Players {
-			if player.ID != defender.ID {
-				return true, player.ID
-			}
-		}
+	// A side is knocked out when its king tower falls; the game ends once
+	// a single side is left standing
+	if king := defender.KingTower(); king != nil && !king.IsAlive() {
+		return be.Eliminate(game, defender.ID)
 	}
 	
-	// For enhanced mode, also check tower count at time limit
-	if game.Mode == models.EnhancedMode {
-		// This will be handled by the game engine when time runs out
+	// Games decided on towers lost are ended by their rule set
+	return false, ""
+}
+
+// Eliminate knocks a player's side out of the game, giving it the best
+// place not yet taken. It reports whether a single side is left standing
+// and, if so, the winner.
+func (be *BattleEngine) Eliminate(game *models.Game, playerID string) (bool, string) {
+	team := game.TeamOf(playerID)
+	if team < 0 {
 		return false, ""
 	}
 	
+	if game.PlaceOf(playerID) == 0 {
+		place := 1
+		for _, other := range be.standingTeams(game) {
+			if other != team {
+				place++
+			}
+		}
+		game.PlaceTeam(team, place)
+	}
+	
+	if standing := be.standingTeams(game); len(standing) == 1 {
+		return true, be.teamWinner(game, standing[0])
+	}
 	return false, ""
 }
 
@@ -198,17 +312,16 @@ func (be *BattleEngine) GetValidTargets(game *models.Game, defenderID string) []
 	
 	validTargets := []int{}
 	
-	if game.Mode == models.SimpleMode {
-		// Simple mode rules: attack in order
-		if defender.Towers[0].IsAlive() {
-			validTargets = append(validTargets, 0)
-		} else if defender.Towers[1].IsAlive() {
-			validTargets = append(validTargets, 1)
-		} else if defender.Towers[2].IsAlive() {
-			validTargets = append(validTargets, 2)
+	if attackRules(game.Mode).OrderedTargets {
+		// Attack in layout order
+		for i, tower := range defender.Towers {
+			if tower.IsAlive() {
+				validTargets = append(validTargets, i)
+				break
+			}
 		}
 	} else {
-		// Enhanced mode: can attack any tower
+		// Any standing tower can be attacked
 		for i, tower := range defender.Towers {
 			if tower.IsAlive() {
 				validTargets = append(validTargets, i)
@@ -219,10 +332,19 @@ func (be *BattleEngine) GetValidTargets(game *models.Game, defenderID string) []
 	return validTargets
 }
 
-// CountDestroyedTowers counts how many towers a player has destroyed
+// CountDestroyedTowers counts how many of a player's own towers have been destroyed
 func (be *BattleEngine) CountDestroyedTowers(player *models.Player) int {
+	return countDestroyed(player.Towers)
+}
+
+// CountTeamDestroyedTowers counts the destroyed towers of a side, counting shared towers once
+func (be *BattleEngine) CountTeamDestroyedTowers(game *models.Game, team int) int {
+	return countDestroyed(game.TeamTowers(team))
+}
+
+func countDestroyed(towers []*models.Tower) int {
 	destroyed := 0
-	for _, tower := range player.Towers {
+	for _, tower := range towers {
 		if !tower.IsAlive() {
 			destroyed++
 		}
@@ -230,35 +352,90 @@ func (be *BattleEngine) CountDestroyedTowers(player *models.Player) int {
 	return destroyed
 }
 
-// GetGameWinner determines the winner based on current game state
-func (be *BattleEngine) GetGameWinner(game *models.Game) string {
-	if len(game.Players) != 2 {
+// standingTeams returns the sides whose king tower still stands
+func (be *BattleEngine) standingTeams(game *models.Game) []int {
+	standing := make([]int, 0, game.Teams())
+	for team := 0; team < game.Teams(); team++ {
+		if game.TeamStanding(team) {
+			standing = append(standing, team)
+		}
+	}
+	return standing
+}
+
+// teamWinner names the player a side's win is recorded under: its first player
+func (be *BattleEngine) teamWinner(game *models.Game, team int) string {
+	members := game.TeamMembers(team)
+	if len(members) == 0 {
 		return ""
 	}
+	return members[0].ID
+}
+
+// RecordPlaces ranks the sides still without a place once a game is over:
+// the winner's side first, then the others by fewest towers lost. Sides
+// level on towers share a place.
+func (be *BattleEngine) RecordPlaces(game *models.Game) {
+	winnerTeam := -1
+	if game.Winner != nil {
+		winnerTeam = game.TeamOf(game.Winner.ID)
+	}
+	rank := func(team int) int {
+		if team == winnerTeam {
+			return -1
+		}
+		return be.CountTeamDestroyedTowers(game, team)
+	}
 	
-	player1 := game.Players[0]
-	player2 := game.Players[1]
+	unplaced := make([]int, 0, game.Teams())
+	for team := 0; team < game.Teams(); team++ {
+		if members := game.TeamMembers(team); len(members) > 0 && game.PlaceOf(members[0].ID) == 0 {
+			unplaced = append(unplaced, team)
+		}
+	}
 	
-	// Check for king tower destruction
-	if !player1.Towers[2].IsAlive() {
-		return player2.ID
+	places := make(map[int]int, len(unplaced))
+	for _, team := range unplaced {
+		places[team] = 1
+		for _, other := range unplaced {
+			if rank(other) < rank(team) {
+				places[team]++
+			}
+		}
 	}
-	if !player2.Towers[2].IsAlive() {
-		return player1.ID
+	for team, place := range places {
+		game.PlaceTeam(team, place)
+	}
+}
+
+// GetGameWinner determines the winner based on current game state. In team
+// games the winner is the first player of the winning side.
+func (be *BattleEngine) GetGameWinner(game *models.Game) string {
+	if len(game.Players) < 2 {
+		return ""
 	}
 	
-	// For enhanced mode, compare tower counts
-	if game.Mode == models.EnhancedMode {
-		destroyed1 := be.CountDestroyedTowers(player1)
-		destroyed2 := be.CountDestroyedTowers(player2)
-		
-		if destroyed2 > destroyed1 {
-			return player2.ID
-		} else if destroyed1 > destroyed2 {
-			return player1.ID
+	// Sides whose king tower fell are beaten
+	standing := be.standingTeams(game)
+	if len(standing) == 1 {
+		return be.teamWinner(game, standing[0])
+	}
+	
+	// Otherwise the side that lost the fewest towers wins
+	winner, fewest, tied := -1, 0, false
+	for _, team := range standing {
+		destroyed := be.CountTeamDestroyedTowers(game, team)
+		switch {
+		case winner < 0 || destroyed < fewest:
+			winner, fewest, tied = team, destroyed, false
+		case destroyed == fewest:
+			tied = true
 		}
-		// If equal, it's a draw (return empty string)
 	}
 	
-	return ""
+	// If equal, it's a draw (return empty string)
+	if winner < 0 || tied {
+		return ""
+	}
+	return be.teamWinner(game, winner)
 }
\ No newline at end of file
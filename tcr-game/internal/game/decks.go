@@ -0,0 +1,91 @@
+// internal/game/decks.go - Building and selecting player decks
+package game
+
+import (
+	"fmt"
+
+	"tcr-game/internal/models"
+)
+
+// DeckList is a player's deck slots and the deck they will play with.
+// Active holds the default deck when the player has not built one.
+type DeckList struct {
+	Decks      []models.Deck `json:"decks"`
+	ActiveSlot int           `json:"active_slot"`
+	Active     []string      `json:"active"`
+}
+
+// Decks returns the player's decks
+func (pp *ProgressionPipeline) Decks(playerID string, balance *Balance) (*DeckList, error) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	player, err := pp.storage.LoadPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+	return deckList(player, balance), nil
+}
+
+// SaveDeck validates a deck against the balance's troops and the player's
+// unlocks and stores it in a slot. Like upgrades, deck edits share the
+// pipeline lock with match rewards.
+func (pp *ProgressionPipeline) SaveDeck(playerID string, slot int, deck models.Deck, balance *Balance) (*DeckList, error) {
+	if slot < 0 || slot >= models.DeckSlots {
+		return nil, fmt.Errorf("deck slot must be between 0 and %d", models.DeckSlots-1)
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	player, err := pp.storage.LoadPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateDeck(deck.Cards, balance.Troops, player.Level); err != nil {
+		return nil, err
+	}
+
+	for len(player.Decks) < models.DeckSlots {
+		player.Decks = append(player.Decks, models.Deck{})
+	}
+	player.Decks[slot] = deck
+
+	if err := pp.storage.SavePlayer(player); err != nil {
+		return nil, err
+	}
+	return deckList(player, balance), nil
+}
+
+// SelectDeck makes the deck in a slot the one the player plays with
+func (pp *ProgressionPipeline) SelectDeck(playerID string, slot int, balance *Balance) (*DeckList, error) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	player, err := pp.storage.LoadPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if slot < 0 || slot >= len(player.Decks) || len(player.Decks[slot].Cards) == 0 {
+		return nil, fmt.Errorf("no deck in slot %d", slot)
+	}
+
+	player.ActiveDeckSlot = slot
+	if err := pp.storage.SavePlayer(player); err != nil {
+		return nil, err
+	}
+	return deckList(player, balance), nil
+}
+
+func deckList(player *models.Player, balance *Balance) *DeckList {
+	list := &DeckList{
+		Decks:      make([]models.Deck, models.DeckSlots),
+		ActiveSlot: player.ActiveDeckSlot,
+	}
+	copy(list.Decks, player.Decks)
+
+	for _, troop := range player.DeckTroops(balance.Troops) {
+		list.Active = append(list.Active, troop.ID)
+	}
+	return list
+}
@@ -0,0 +1,450 @@
+// internal/game/draft.go - Draft mode: a pick and ban phase before a simple match
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"tcr-game/internal/models"
+)
+
+// DraftStepKind is whether a draft step picks or bans a troop
+type DraftStepKind string
+
+const (
+	DraftPick DraftStepKind = "pick"
+	DraftBan  DraftStepKind = "ban"
+)
+
+// DraftStep is one pick or ban, made by one player
+type DraftStep struct {
+	PlayerID string        `json:"player_id"`
+	Kind     DraftStepKind `json:"kind"`
+}
+
+// DraftAction picks or bans a troop from the pool
+type DraftAction struct {
+	Type    DraftStepKind `json:"type"` // "pick" or "ban"
+	TroopID string        `json:"troop_id"`
+}
+
+type DraftResult struct {
+	Success  bool                   `json:"success"`
+	Draft    map[string]interface{} `json:"draft,omitempty"`
+	Complete bool                   `json:"complete"` // the match has started with the drafted troops
+	Error    string                 `json:"error,omitempty"`
+}
+
+// draft is one game's pick and ban phase. Its mutex serializes picks with
+// the pick timer, like the simple mode turn clock.
+type draft struct {
+	game     *models.Game
+	order    []DraftStep
+	step     int      // index of the current step in order
+	pool     []string // troops still available, in catalogue order
+	bans     map[string][]string
+	picks    map[string][]string
+	timer    *time.Timer
+	deadline time.Time
+	mutex    sync.Mutex
+}
+
+// DraftGameManager runs draft mode. Once both players join, the game
+// enters the Drafting state and players alternate banning and then picking
+// troops from the catalogue. The picks become the players' troops and the
+// match is played under simple mode rules.
+type DraftGameManager struct {
+	simple       *SimpleGameManager
+	catalogue    *models.TroopCatalogue
+	bans         int
+	picks        int
+	pickTime     int // seconds, 0 leaves picks untimed
+	eventManager *EventManager
+	drafts       map[string]*draft
+	mutex        sync.Mutex
+}
+
+func NewDraftGameManager(simple *SimpleGameManager, catalogue *models.TroopCatalogue, bans, picks, pickTime int) *DraftGameManager {
+	if picks <= 0 {
+		picks = 3
+	}
+
+	return &DraftGameManager{
+		simple:    simple,
+		catalogue: catalogue,
+		bans:      bans,
+		picks:     picks,
+		pickTime:  pickTime,
+		drafts:    make(map[string]*draft),
+	}
+}
+
+func init() {
+	RegisterRuleSet(RuleSetDefinition{
+		Mode:   models.DraftMode,
+		Attack: AttackRules{OrderedTargets: true, Retaliation: true, ContinueOnKill: true},
+		Experience: func(balance *Balance) ExperienceRewards {
+			return ExperienceRewards{Win: balance.Game.Draft.ExpWin, Draw: balance.Game.Draft.ExpDraw}
+		},
+		New: func(balance *Balance, events *EventManager) RuleSet {
+			simple := NewSimpleGameManager(
+				balance.Game.Simple.MaxPlayers,
+				balance.Game.Simple.TurnTime,
+				balance.Game.Simple.MaxTimeouts,
+				balance.Game.Enhanced.CritMultiplier,
+			)
+			simple.SetEventManager(events)
+
+			manager := NewDraftGameManager(
+				simple,
+				balance.Troops,
+				balance.Game.Draft.BansPerPlayer,
+				balance.Game.Draft.PicksPerPlayer,
+				balance.Game.Draft.PickTime,
+			)
+			manager.SetEventManager(events)
+			return manager
+		},
+	})
+}
+
+// SetEventManager registers the event manager draft updates are published to
+func (dgm *DraftGameManager) SetEventManager(eventManager *EventManager) {
+	dgm.eventManager = eventManager
+}
+
+// StartGame opens the draft. Bans alternate between the players, then picks do.
+func (dgm *DraftGameManager) StartGame(game *models.Game) error {
+	if len(game.Players) != 2 {
+		return fmt.Errorf("need exactly 2 players, got %d", len(game.Players))
+	}
+
+	needed := 2 * (dgm.bans + dgm.picks)
+	if dgm.catalogue == nil || dgm.catalogue.Len() < needed {
+		return fmt.Errorf("draft needs %d troops in the catalogue", needed)
+	}
+
+	d := &draft{
+		game:  game,
+		bans:  make(map[string][]string),
+		picks: make(map[string][]string),
+	}
+	for _, troop := range dgm.catalogue.All() {
+		d.pool = append(d.pool, troop.ID)
+	}
+	for i := 0; i < 2*dgm.bans; i++ {
+		d.order = append(d.order, DraftStep{PlayerID: game.Players[i%2].ID, Kind: DraftBan})
+	}
+	for i := 0; i < 2*dgm.picks; i++ {
+		d.order = append(d.order, DraftStep{PlayerID: game.Players[i%2].ID, Kind: DraftPick})
+	}
+
+	game.State = models.Drafting
+	for _, player := range game.Players {
+		player.AvailableTroops = nil
+	}
+
+	dgm.mutex.Lock()
+	dgm.drafts[game.ID] = d
+	dgm.mutex.Unlock()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	dgm.nextStep(d)
+	return nil
+}
+
+func (dgm *DraftGameManager) draft(gameID string) *draft {
+	dgm.mutex.Lock()
+	defer dgm.mutex.Unlock()
+
+	return dgm.drafts[gameID]
+}
+
+// ProcessDraft plays a pick or ban for the player whose step it is
+func (dgm *DraftGameManager) ProcessDraft(game *models.Game, playerID string, action DraftAction) (*DraftResult, error) {
+	d := dgm.draft(game.ID)
+	if d == nil {
+		return &DraftResult{Success: false, Error: "game is not drafting"}, nil
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := dgm.validateDraft(d, playerID, action); err != nil {
+		return &DraftResult{Success: false, Error: err.Error(), Draft: dgm.draftState(d)}, nil
+	}
+
+	dgm.applyStep(d, action.TroopID)
+	return &DraftResult{
+		Success:  true,
+		Draft:    dgm.draftState(d),
+		Complete: game.State != models.Drafting,
+	}, nil
+}
+
+// validateDraft checks an action is the current step and its troop is in
+// the pool. Callers hold the draft lock.
+func (dgm *DraftGameManager) validateDraft(d *draft, playerID string, action DraftAction) error {
+	if d.game.State != models.Drafting || d.step >= len(d.order) {
+		return errors.New("game is not drafting")
+	}
+
+	step := d.order[d.step]
+	if step.PlayerID != playerID {
+		return errors.New("not your turn to draft")
+	}
+	if step.Kind != action.Type {
+		return fmt.Errorf("expected a %s", step.Kind)
+	}
+	if poolIndex(d.pool, action.TroopID) < 0 {
+		return fmt.Errorf("troop %s is not in the pool", action.TroopID)
+	}
+	return nil
+}
+
+// applyStep takes a troop out of the pool for the current step and moves
+// to the next one. Callers hold the draft lock.
+func (dgm *DraftGameManager) applyStep(d *draft, troopID string) {
+	step := d.order[d.step]
+	index := poolIndex(d.pool, troopID)
+	d.pool = append(d.pool[:index], d.pool[index+1:]...)
+
+	if step.Kind == DraftBan {
+		d.bans[step.PlayerID] = append(d.bans[step.PlayerID], troopID)
+	} else {
+		d.picks[step.PlayerID] = append(d.picks[step.PlayerID], troopID)
+	}
+	d.step++
+
+	if d.step < len(d.order) {
+		dgm.nextStep(d)
+		return
+	}
+	dgm.finishDraft(d)
+}
+
+// nextStep restarts the pick timer and announces the step. Callers hold the draft lock.
+func (dgm *DraftGameManager) nextStep(d *draft) {
+	if dgm.pickTime > 0 {
+		step := d.step
+		duration := time.Duration(dgm.pickTime) * time.Second
+
+		dgm.mutex.Lock()
+		if d.timer != nil {
+			d.timer.Stop()
+		}
+		d.deadline = time.Now().Add(duration)
+		d.timer = time.AfterFunc(duration, func() {
+			dgm.pickTimedOut(d, step)
+		})
+		dgm.mutex.Unlock()
+	}
+
+	if dgm.eventManager != nil {
+		dgm.eventManager.PublishDraftUpdated(d.game.ID, dgm.draftState(d))
+	}
+}
+
+// pickTimedOut makes the step of a player who let the timer run out for
+// them, with the first troop left in the pool
+func (dgm *DraftGameManager) pickTimedOut(d *draft, step int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if dgm.draft(d.game.ID) != d || d.step != step || d.game.State != models.Drafting {
+		return
+	}
+	dgm.applyStep(d, d.pool[0])
+}
+
+// finishDraft gives each player their picks at their troop levels and
+// starts the match. Callers hold the draft lock.
+func (dgm *DraftGameManager) finishDraft(d *draft) {
+	dgm.stopDraft(d.game.ID)
+
+	for _, player := range d.game.Players {
+		troops := make([]*models.Troop, 0, len(d.picks[player.ID]))
+		for _, id := range d.picks[player.ID] {
+			troop, _ := dgm.catalogue.Get(id)
+			troop.ApplyLevel(player.GetTroopLevel(id))
+			troops = append(troops, troop)
+		}
+		player.AvailableTroops = troops
+	}
+
+	if dgm.eventManager != nil {
+		state := dgm.draftState(d)
+		state["complete"] = true
+		dgm.eventManager.PublishDraftUpdated(d.game.ID, state)
+	}
+
+	dgm.simple.beginMatch(d.game)
+}
+
+// stopDraft stops and forgets a game's draft without taking the draft lock
+func (dgm *DraftGameManager) stopDraft(gameID string) {
+	dgm.mutex.Lock()
+	defer dgm.mutex.Unlock()
+
+	d, exists := dgm.drafts[gameID]
+	if !exists {
+		return
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	delete(dgm.drafts, gameID)
+}
+
+// draftState projects a draft for clients. Callers hold the draft lock.
+func (dgm *DraftGameManager) draftState(d *draft) map[string]interface{} {
+	state := map[string]interface{}{
+		"pool":  append([]string(nil), d.pool...),
+		"bans":  copyDraftLists(d.bans),
+		"picks": copyDraftLists(d.picks),
+		"step":  d.step,
+		"steps": len(d.order),
+	}
+	if d.step < len(d.order) {
+		state["current"] = d.order[d.step]
+
+		dgm.mutex.Lock()
+		if d.timer != nil {
+			remaining := int(math.Ceil(time.Until(d.deadline).Seconds()))
+			if remaining < 0 {
+				remaining = 0
+			}
+			state["pick_remaining"] = remaining
+		}
+		dgm.mutex.Unlock()
+	}
+	return state
+}
+
+func copyDraftLists(lists map[string][]string) map[string][]string {
+	copied := make(map[string][]string, len(lists))
+	for playerID, troops := range lists {
+		copied[playerID] = append([]string(nil), troops...)
+	}
+	return copied
+}
+
+func poolIndex(pool []string, troopID string) int {
+	for i, id := range pool {
+		if id == troopID {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateAction decodes a DraftAction while drafting and a TurnAction afterwards
+func (dgm *DraftGameManager) ValidateAction(game *models.Game, playerID string, payload json.RawMessage) (interface{}, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return nil, errors.New("invalid action")
+	}
+
+	if header.Type != string(DraftPick) && header.Type != string(DraftBan) {
+		return dgm.simple.ValidateAction(game, playerID, payload)
+	}
+
+	var action DraftAction
+	if err := json.Unmarshal(payload, &action); err != nil {
+		return nil, errors.New("invalid action")
+	}
+	if action.TroopID == "" {
+		return nil, errors.New("troop_id is required")
+	}
+	return action, nil
+}
+
+// ApplyAction plays a pick or ban, or a turn of the drafted match
+func (dgm *DraftGameManager) ApplyAction(game *models.Game, playerID string, action interface{}) (ActionResult, error) {
+	if draftAction, ok := action.(DraftAction); ok {
+		return dgm.ProcessDraft(game, playerID, draftAction)
+	}
+	return dgm.simple.ApplyAction(game, playerID, action)
+}
+
+// State projects the draft while drafting and the match afterwards
+func (dgm *DraftGameManager) State(game *models.Game) (map[string]interface{}, error) {
+	if d := dgm.draft(game.ID); d != nil {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		players := make([]map[string]interface{}, len(game.Players))
+		for i, player := range game.Players {
+			players[i] = map[string]interface{}{
+				"id":       player.ID,
+				"username": player.Username,
+			}
+		}
+		return map[string]interface{}{
+			"mode":            game.Mode,
+			"state":           game.State,
+			"seed":            game.Seed,
+			"balance_version": game.BalanceVersion,
+			"players":         players,
+			"draft":           dgm.draftState(d),
+		}, nil
+	}
+	return dgm.simple.State(game)
+}
+
+// End ends a game early. A game ended while drafting is a draw.
+func (dgm *DraftGameManager) End(game *models.Game, reason string) error {
+	if d := dgm.draft(game.ID); d != nil {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		dgm.stopDraft(game.ID)
+	}
+	return dgm.simple.End(game, reason)
+}
+
+// Forfeit knocks out a player who left the game. Leaving during the
+// draft loses the match to the opponent.
+func (dgm *DraftGameManager) Forfeit(game *models.Game, playerID string, reason string) error {
+	if d := dgm.draft(game.ID); d != nil {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		if game.State == models.Drafting {
+			dgm.stopDraft(game.ID)
+			_, winnerID := dgm.simple.battleEngine.Eliminate(game, playerID)
+			game.Winner = dgm.simple.findPlayerByID(game, winnerID)
+			return dgm.simple.End(game, reason)
+		}
+	}
+	return dgm.simple.Forfeit(game, playerID, reason)
+}
+
+// Pause stops the match's turn clock. Draft picks keep their timer.
+func (dgm *DraftGameManager) Pause(game *models.Game) {
+	dgm.simple.Pause(game)
+}
+
+func (dgm *DraftGameManager) Resume(game *models.Game) {
+	dgm.simple.Resume(game)
+}
+
+func (dgm *DraftGameManager) CleanupGame(gameID string) {
+	dgm.stopDraft(gameID)
+	dgm.simple.CleanupGame(gameID)
+}
+
+// Accepted reports whether the pick or ban was made
+func (dr *DraftResult) Accepted() bool {
+	return dr.Success
+}
+
+func (dr *DraftResult) Battle() *BattleResult {
+	return nil
+}
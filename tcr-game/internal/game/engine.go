@@ -2,8 +2,12 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"sync"
+	"time"
 	
 	"tcr-game/config"
 	"tcr-game/internal/models"
@@ -12,46 +16,261 @@ import (
 
 type GameEngine struct {
 	storage         *storage.JSONStorage
-	simpleManager   *SimpleGameManager
-	enhancedManager *EnhancedGameManager
+	gameStorage     *storage.GameStorage
+	rules           *balanceRules         // rules new games are created with
+	rulesByVersion  map[int]*balanceRules // rules of every balance version loaded
 	activeGames     map[string]*models.Game
+	events          *EventManager
+	progression     *ProgressionPipeline
+	resultsMutex    sync.Mutex     // runs the post-match pipeline one game at a time
+	results         sync.WaitGroup // post-match pipelines still running
+	retention       time.Duration  // how long a finished game stays loaded
 	mutex           sync.RWMutex
+	presence        map[string]*gamePresence // gameID -> its players' connections
+	presenceMutex   sync.Mutex
 	config          *config.Config
 }
 
-func NewGameEngine(cfg *config.Config, storage *storage.JSONStorage) *GameEngine {
-	simpleManager := NewSimpleGameManager(
-		cfg.Game.Simple.MaxPlayers,
-		cfg.Game.Simple.TurnTime,
-		cfg.Game.Enhanced.CritMultiplier,
-	)
-	
-	enhancedManager := NewEnhancedGameManager(
-		cfg.Game.Enhanced.ManaRegen,
-		cfg.Game.Enhanced.GameDuration,
-		cfg.Game.Enhanced.ExpWin,
-		cfg.Game.Enhanced.ExpDraw,
-		cfg.Game.Enhanced.CritMultiplier,
-	)
-	
-	return &GameEngine{
+// balanceRules are the rule sets of every registered mode configured from one balance version
+type balanceRules struct {
+	balance         *Balance
+	ruleSets        map[models.GameMode]RuleSet
+	replayEngine    *ReplayEngine
+}
+
+func NewGameEngine(cfg *config.Config, storage *storage.JSONStorage, gameStorage *storage.GameStorage, balance *Balance) *GameEngine {
+	ge := &GameEngine{
 		storage:         storage,
-		simpleManager:   simpleManager,
-		enhancedManager: enhancedManager,
+		gameStorage:     gameStorage,
+		rulesByVersion:  make(map[int]*balanceRules),
 		activeGames:     make(map[string]*models.Game),
+		events:          NewEventManager(),
+		progression:     NewProgressionPipeline(storage, NewRatingSystem(cfg.Rating)),
+		presence:        make(map[string]*gamePresence),
+		retention:       DefaultGameRetention,
 		config:          cfg,
 	}
+	ge.events.Handle(EventGameEnded, ge.handleGameEnd)
+	ge.SetBalance(balance)
+	
+	return ge
+}
+
+// SetBalance makes balance the version new games are created with. Games
+// already created keep the rule sets of the version they started on.
+func (ge *GameEngine) SetBalance(balance *Balance) {
+	rules := ge.newBalanceRules(balance)
+	
+	ge.mutex.Lock()
+	defer ge.mutex.Unlock()
+	
+	ge.rules = rules
+	ge.rulesByVersion[balance.Version] = rules
+}
+
+// Balance returns the balance version new games are created with
+func (ge *GameEngine) Balance() *Balance {
+	ge.mutex.RLock()
+	defer ge.mutex.RUnlock()
+	
+	return ge.rules.balance
+}
+
+// Modes returns the modes games can be created in
+func (ge *GameEngine) Modes() []models.GameMode {
+	ge.mutex.RLock()
+	defer ge.mutex.RUnlock()
+	
+	modes := make([]models.GameMode, 0, len(ge.rules.ruleSets))
+	for mode := range ge.rules.ruleSets {
+		modes = append(modes, mode)
+	}
+	return modes
+}
+
+func (ge *GameEngine) newBalanceRules(balance *Balance) *balanceRules {
+	return &balanceRules{
+		balance:         balance,
+		ruleSets:        newRuleSets(balance, ge.events),
+		replayEngine:    NewReplayEngine(balance.Game.Enhanced.CritMultiplier),
+	}
+}
+
+// rulesFor returns the rule sets of the balance version a game was created with
+func (ge *GameEngine) rulesFor(game *models.Game) *balanceRules {
+	ge.mutex.RLock()
+	defer ge.mutex.RUnlock()
+	
+	if rules, exists := ge.rulesByVersion[game.BalanceVersion]; exists {
+		return rules
+	}
+	return ge.rules
+}
+
+// ruleSet returns the rule set a game is played under
+func (ge *GameEngine) ruleSet(game *models.Game) (RuleSet, error) {
+	ruleSet, exists := ge.rulesFor(game).ruleSets[game.Mode]
+	if !exists {
+		return nil, errors.New("invalid game mode")
+	}
+	return ruleSet, nil
+}
+
+// Events returns the event manager every game of the engine publishes to
+func (ge *GameEngine) Events() *EventManager {
+	return ge.events
+}
+
+// handleGameEnd hands a game of any mode that ended to the post-match
+// pipeline and forgets who is connected to it. It runs inside Publish, under whatever lock ended the game,
+// so the pipeline's disk writes happen on a goroutine of their own.
+func (ge *GameEngine) handleGameEnd(event GameEventData) {
+	if event.Game != nil {
+		// Presence is forgotten under the presence lock, which is held
+		// while presence events are published, so not on this goroutine
+		go ge.forgetPresence(event.Game.ID)
+		ge.results.Add(1)
+		go ge.recordResult(event.Game)
+	}
+}
+
+// recordResult is the post-match pipeline: it saves the replay, then
+// persists experience, stats and rating and publishes each player's rewards.
+// The game is retired once the pipeline is done.
+func (ge *GameEngine) recordResult(game *models.Game) {
+	defer ge.results.Done()
+	defer ge.retire(game)
+	ge.resultsMutex.Lock()
+	defer ge.resultsMutex.Unlock()
+	
+	ge.saveReplay(game)
+	
+	balance := ge.rulesFor(game).balance
+	experience := experienceFor(game.Mode, balance)
+	
+	rewards, err := ge.progression.Process(game, experience, balance.upgrades().Rewards)
+	if err != nil {
+		log.Printf("Failed to record result of game %s: %v", game.ID, err)
+		return
+	}
+	ge.events.PublishMatchRewards(game.ID, rewards)
+}
+
+// DefaultGameRetention is how long a finished game stays loaded unless set
+// otherwise
+const DefaultGameRetention = 5 * time.Minute
+
+// SetGameRetention sets how long a finished game stays loaded, so clients
+// can still fetch its final state, rewards and missed events
+func (ge *GameEngine) SetGameRetention(retention time.Duration) {
+	ge.mutex.Lock()
+	defer ge.mutex.Unlock()
+	
+	ge.retention = retention
+}
+
+// retire cleans a finished game up once its retention has passed, unless
+// it was already cleaned up and its ID reused
+func (ge *GameEngine) retire(game *models.Game) {
+	ge.mutex.RLock()
+	retention := ge.retention
+	ge.mutex.RUnlock()
+	
+	time.AfterFunc(retention, func() {
+		if current, err := ge.GetGame(game.ID); err == nil && current == game {
+			ge.CleanupGame(game.ID)
+		}
+	})
+}
+
+// WaitForResults blocks until the post-match pipeline of every game that
+// has ended is done
+func (ge *GameEngine) WaitForResults() {
+	ge.results.Wait()
+}
+
+// GetMatchRewards returns what each player earned from a finished game
+func (ge *GameEngine) GetMatchRewards(gameID string) (map[string]*MatchRewards, bool) {
+	return ge.progression.Rewards(gameID)
+}
+
+// UpgradePlayer spends a player's gold and cards on one level of a troop or
+// tower. The new level applies from the next game the player joins.
+func (ge *GameEngine) UpgradePlayer(playerID string, request UpgradeRequest) (*UpgradeResult, error) {
+	return ge.progression.Upgrade(playerID, request, ge.Balance())
+}
+
+func (ge *GameEngine) GetUpgradeOptions(playerID string) (*UpgradeOptions, error) {
+	return ge.progression.UpgradeOptions(playerID, ge.Balance())
+}
+
+// GetDecks returns a player's deck slots and active deck
+func (ge *GameEngine) GetDecks(playerID string) (*DeckList, error) {
+	return ge.progression.Decks(playerID, ge.Balance())
+}
+
+// SaveDeck stores a deck in one of the player's slots. The active deck is
+// dealt from the next game the player joins.
+func (ge *GameEngine) SaveDeck(playerID string, slot int, deck models.Deck) (*DeckList, error) {
+	return ge.progression.SaveDeck(playerID, slot, deck, ge.Balance())
+}
+
+func (ge *GameEngine) SelectDeck(playerID string, slot int) (*DeckList, error) {
+	return ge.progression.SelectDeck(playerID, slot, ge.Balance())
+}
+
+// saveReplay persists the replay log of a finished game
+func (ge *GameEngine) saveReplay(game *models.Game) {
+	if game.Replay == nil {
+		return
+	}
+	
+	if err := ge.gameStorage.SaveReplay(game.Replay); err != nil {
+		log.Printf("Failed to save replay for game %s: %v", game.ID, err)
+	}
+}
+
+func (ge *GameEngine) GetReplay(gameID string) (*models.Replay, error) {
+	return ge.gameStorage.LoadReplay(gameID)
+}
+
+// VerifyReplay re-simulates a replay with the balance it was recorded under
+// and checks it reaches the recorded result. Replays from versions loaded
+// before the last restart fall back to the current balance.
+func (ge *GameEngine) VerifyReplay(replay *models.Replay) error {
+	ge.mutex.RLock()
+	rules, exists := ge.rulesByVersion[replay.BalanceVersion]
+	if !exists {
+		rules = ge.rules
+	}
+	ge.mutex.RUnlock()
+	
+	return rules.replayEngine.Verify(replay)
 }
 
 func (ge *GameEngine) CreateGame(gameID string, mode models.GameMode) (*models.Game, error) {
+	return ge.CreateGameWithPlayers(gameID, mode, 0)
+}
+
+// CreateGameWithPlayers creates a game seated for a number of players the
+// mode allows; zero seats the mode's usual number
+func (ge *GameEngine) CreateGameWithPlayers(gameID string, mode models.GameMode, players int) (*models.Game, error) {
 	ge.mutex.Lock()
 	defer ge.mutex.Unlock()
 	
 	if _, exists := ge.activeGames[gameID]; exists {
 		return nil, errors.New("game already exists")
 	}
+	if _, exists := ge.rules.ruleSets[mode]; !exists {
+		return nil, errors.New("invalid game mode")
+	}
 	
-	game := models.NewGame(gameID, mode)
+	game, err := newGame(gameID, mode, players)
+	if err != nil {
+		return nil, err
+	}
+	game.BalanceVersion = ge.rules.balance.Version
+	game.TowerCatalogue = ge.rules.balance.Towers
 	ge.activeGames[gameID] = game
 	
 	return game, nil
@@ -66,80 +285,115 @@ func (ge *GameEngine) JoinGame(gameID string, player *models.Player) error {
 		return errors.New("game not found")
 	}
 	
-	if !game.AddPlayer(player) {
+	if game.TeamOf(player.ID) >= 0 {
+		return errors.New("player already in game")
+	}
+	if game.IsFull() {
 		return errors.New("game is full")
 	}
 	
-	// Load available troops for the player
-	if err := ge.loadPlayerTroops(player); err != nil {
+	// Load available troops for the player before seating them, so a
+	// player without troops never takes a seat
+	rules := ge.rulesByVersion[game.BalanceVersion]
+	if err := ge.loadPlayerTroops(player, rules.balance.Troops); err != nil {
 		return err
 	}
+	game.AddPlayer(player)
+	ge.events.PublishPlayerJoined(gameID, player, game.TeamOf(player.ID))
 	
-	// Start game if we have enough players
-	if len(game.Players) == 2 {
-		return ge.startGame(game)
+	// Start game once every seat is taken
+	if game.IsFull() {
+		ruleSet, exists := rules.ruleSets[game.Mode]
+		if !exists {
+			return errors.New("invalid game mode")
+		}
+		if err := ruleSet.StartGame(game); err != nil {
+			return err
+		}
+		ge.events.PublishGameStarted(gameID, game)
 	}
 	
 	return nil
 }
 
-func (ge *GameEngine) startGame(game *models.Game) error {
-	switch game.Mode {
-	case models.SimpleMode:
-		return ge.simpleManager.StartGame(game)
-	case models.EnhancedMode:
-		return ge.enhancedManager.StartGame(game)
-	default:
-		return errors.New("invalid game mode")
+func (ge *GameEngine) loadPlayerTroops(player *models.Player, catalogue *models.TroopCatalogue) error {
+	// Players bring their active deck, leveled by their upgrades
+	player.AvailableTroops = player.DeckTroops(catalogue)
+	if len(player.AvailableTroops) == 0 {
+		return errors.New("no troops available")
 	}
+	
+	return nil
 }
 
-func (ge *GameEngine) loadPlayerTroops(player *models.Player) error {
-	troops, err := ge.storage.LoadTroops()
+// ProcessAction decodes an action sent by a client and plays it under the
+// rule set of the game's mode
+func (ge *GameEngine) ProcessAction(gameID, playerID string, payload json.RawMessage) (ActionResult, error) {
+	game, err := ge.GetGame(gameID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	
-	player.AvailableTroops = make([]*models.Troop, len(troops))
-	for i, troop := range troops {
-		playerTroop := *troop
-		playerTroop.ApplyLevel(player.GetTroopLevel(troop.ID))
-		player.AvailableTroops[i] = &playerTroop
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return nil, err
 	}
 	
-	return nil
+	action, err := ruleSet.ValidateAction(game, playerID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return ge.playAction(ruleSet, game, playerID, action)
 }
 
 func (ge *GameEngine) ProcessSimpleAction(gameID, playerID string, action TurnAction) (*TurnResult, error) {
-	ge.mutex.RLock()
-	game, exists := ge.activeGames[gameID]
-	ge.mutex.RUnlock()
-	
-	if !exists {
-		return nil, errors.New("game not found")
-	}
-	
-	if game.Mode != models.SimpleMode {
-		return nil, errors.New("game is not in simple mode")
+	result, err := ge.applyAction(gameID, playerID, models.SimpleMode, action)
+	if err != nil {
+		return nil, err
 	}
-	
-	return ge.simpleManager.ProcessTurn(game, playerID, action)
+	return result.(*TurnResult), nil
 }
 
 func (ge *GameEngine) ProcessEnhancedAction(gameID, playerID string, action EnhancedAction) (*EnhancedResult, error) {
-	ge.mutex.RLock()
-	game, exists := ge.activeGames[gameID]
-	ge.mutex.RUnlock()
+	result, err := ge.applyAction(gameID, playerID, models.EnhancedMode, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*EnhancedResult), nil
+}
+
+// applyAction plays an already decoded action in a game of the given mode
+func (ge *GameEngine) applyAction(gameID, playerID string, mode models.GameMode, action interface{}) (ActionResult, error) {
+	game, err := ge.GetGame(gameID)
+	if err != nil {
+		return nil, err
+	}
 	
-	if !exists {
-		return nil, errors.New("game not found")
+	if game.Mode != mode {
+		return nil, fmt.Errorf("game is not in %s mode", mode)
 	}
 	
-	if game.Mode != models.EnhancedMode {
-		return nil, errors.New("game is not in enhanced mode")
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return nil, err
+	}
+	return ge.playAction(ruleSet, game, playerID, action)
+}
+
+// playAction applies an action and publishes its result, along with the
+// attack it made and the tower it destroyed. The game's events are held
+// while the rules apply it, so these come before the turn change or game
+// end the action caused.
+func (ge *GameEngine) playAction(ruleSet RuleSet, game *models.Game, playerID string, action interface{}) (ActionResult, error) {
+	release := ge.events.Hold(game.ID)
+	result, err := ruleSet.ApplyAction(game, playerID, action)
+	if err != nil {
+		release()
+		return nil, err
 	}
 	
-	return ge.enhancedManager.ProcessAction(gameID, playerID, action)
+	release(ActionEvents(game.ID, playerID, result)...)
+	return result, nil
 }
 
 func (ge *GameEngine) GetGame(gameID string) (*models.Game, error) {
@@ -154,52 +408,83 @@ func (ge *GameEngine) GetGame(gameID string) (*models.Game, error) {
 	return game, nil
 }
 
+// GetGameState projects a game's state as spectators see it, with every
+// player's hand left out
 func (ge *GameEngine) GetGameState(gameID string) (map[string]interface{}, error) {
+	return ge.GetGameStateFor(gameID, "")
+}
+
+// GetGameStateFor projects a game's state as one player sees it. Hands are
+// private, so only the player's own is included.
+func (ge *GameEngine) GetGameStateFor(gameID, playerID string) (map[string]interface{}, error) {
 	game, err := ge.GetGame(gameID)
 	if err != nil {
 		return nil, err
 	}
 	
-	switch game.Mode {
-	case models.SimpleMode:
-		return ge.simpleManager.GetGameState(game), nil
-	case models.EnhancedMode:
-		return ge.enhancedManager.GetGameState(gameID)
-	default:
-		return nil, errors.New("invalid game mode")
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return nil, err
+	}
+	
+	state, err := ruleSet.State(game)
+	if err != nil {
+		return nil, err
+	}
+	
+	if rewards, exists := ge.progression.Rewards(gameID); exists {
+		state["rewards"] = rewards
 	}
+	if state["state"] != models.Finished {
+		state["presence"] = ge.presenceState(game)
+	}
+	
+	if players, ok := state["players"].([]map[string]interface{}); ok {
+		for _, player := range players {
+			if player["id"] != playerID {
+				delete(player, "hand")
+				delete(player, "next_card")
+			}
+		}
+	}
+	return state, nil
 }
 
+// EndGame ends a game early. The engine lock is not held while the rule
+// set ends the game, since the post-match pipeline runs on the same call.
 func (ge *GameEngine) EndGame(gameID string, reason string) error {
-	ge.mutex.Lock()
-	defer ge.mutex.Unlock()
-	
-	game, exists := ge.activeGames[gameID]
-	if !exists {
-		return errors.New("game not found")
+	game, err := ge.GetGame(gameID)
+	if err != nil {
+		return err
 	}
 	
-	switch game.Mode {
-	case models.SimpleMode:
-		return ge.simpleManager.EndGame(game, reason)
-	case models.EnhancedMode:
-		ge.enhancedManager.CleanupGame(gameID)
-		return nil
-	default:
-		return errors.New("invalid game mode")
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return err
 	}
+	
+	err = ruleSet.End(game, reason)
+	ruleSet.CleanupGame(gameID)
+	return err
 }
 
 func (ge *GameEngine) CleanupGame(gameID string) {
 	ge.mutex.Lock()
-	defer ge.mutex.Unlock()
+	game, exists := ge.activeGames[gameID]
+	delete(ge.activeGames, gameID)
+	ge.mutex.Unlock()
 	
-	if game, exists := ge.activeGames[gameID]; exists {
-		if game.Mode == models.EnhancedMode {
-			ge.enhancedManager.CleanupGame(gameID)
-		}
-		delete(ge.activeGames, gameID)
+	// The rule set is cleaned up without the engine lock, since a game
+	// ending at the same moment runs the post-match pipeline
+	if !exists {
+		return
+	}
+	if ruleSet, err := ge.ruleSet(game); err == nil {
+		ruleSet.CleanupGame(gameID)
 	}
+	ge.forgetPresence(gameID)
+	ge.progression.Forget(gameID)
+	ge.events.CleanupGame(gameID)
 }
 
 func (ge *GameEngine) GetActiveGames() map[string]*models.Game {
@@ -2,6 +2,7 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -14,25 +15,62 @@ type EnhancedGameManager struct {
 	battleEngine    *BattleEngine
 	manaRegenRate   float64
 	gameDuration    int // seconds
-	expWin          int
-	expDraw         int
+	overtime        int // seconds, 0 skips overtime
+	overtimeManaFactor float64
+	suddenDeath     int // seconds, 0 skips sudden death
+	tickInterval    time.Duration
+	arenaConfig     ArenaConfig
 	activeGames     map[string]*EnhancedGameState
+	eventManager    *EventManager
 	mutex           sync.RWMutex
 }
 
 type EnhancedGameState struct {
 	Game          *models.Game
+	Arena         *Arena
+	Hands         map[string]*Hand // player ID -> hand dealt from their deck
 	StartTime     time.Time
+	Phase         MatchPhase
+	PhaseEnds     time.Time
 	LastManaUpdate time.Time
 	GameTimer     *time.Timer
 	ManaTimer     *time.Ticker  // Correctly typed as Ticker
+	TickTimer     *time.Ticker
 	GameEnded     bool
+	done          chan struct{}
+	stopOnce      sync.Once
 	mutex         sync.RWMutex
 }
 
+// timeLeft returns the whole seconds left in the current phase
+func (gs *EnhancedGameState) timeLeft() int {
+	timeLeft := int(time.Until(gs.PhaseEnds).Seconds())
+	if timeLeft < 0 {
+		timeLeft = 0
+	}
+	return timeLeft
+}
+
+// stopTimers stops every timer of the game and releases its background goroutines
+func (gs *EnhancedGameState) stopTimers() {
+	gs.stopOnce.Do(func() {
+		if gs.GameTimer != nil {
+			gs.GameTimer.Stop()
+		}
+		if gs.ManaTimer != nil {
+			gs.ManaTimer.Stop()
+		}
+		if gs.TickTimer != nil {
+			gs.TickTimer.Stop()
+		}
+		close(gs.done)
+	})
+}
+
 type EnhancedAction struct {
 	Type         string    `json:"type"`        // "spawn_troop"
 	TroopID      string    `json:"troop_id"`    
+	TargetPlayer string    `json:"target_player,omitempty"` // needed when there is more than one opponent
 	TargetTower  int       `json:"target_tower"`
 	Timestamp    time.Time `json:"timestamp"`
 }
@@ -40,6 +78,8 @@ type EnhancedAction struct {
 type EnhancedResult struct {
 	Success       bool          `json:"success"`
 	BattleResult  *BattleResult `json:"battle_result,omitempty"`
+	Unit          *ArenaUnitState `json:"unit,omitempty"`
+	Hand          []string      `json:"hand,omitempty"` // the player's hand after the play
 	PlayerMana    int           `json:"player_mana"`
 	GameTimeLeft  int           `json:"game_time_left_seconds"`
 	GameEnded     bool          `json:"game_ended"`
@@ -47,17 +87,27 @@ type EnhancedResult struct {
 	Error         string        `json:"error,omitempty"`
 }
 
-func NewEnhancedGameManager(manaRegenRate float64, gameDuration, expWin, expDraw int, critMultiplier float64) *EnhancedGameManager {
+func NewEnhancedGameManager(manaRegenRate float64, gameDuration int, critMultiplier float64, tickIntervalMs int) *EnhancedGameManager {
+	if tickIntervalMs <= 0 {
+		tickIntervalMs = 250
+	}
+	
 	return &EnhancedGameManager{
 		battleEngine:  NewBattleEngine(critMultiplier),
 		manaRegenRate: manaRegenRate,
 		gameDuration:  gameDuration,
-		expWin:        expWin,
-		expDraw:       expDraw,
+		overtimeManaFactor: 1,
+		tickInterval:  time.Duration(tickIntervalMs) * time.Millisecond,
+		arenaConfig:   DefaultArenaConfig(),
 		activeGames:   make(map[string]*EnhancedGameState),
 	}
 }
 
+// SetEventManager registers the event manager arena ticks and game endings are published to
+func (egm *EnhancedGameManager) SetEventManager(eventManager *EventManager) {
+	egm.eventManager = eventManager
+}
+
 // StartGame initializes an enhanced mode game
 func (egm *EnhancedGameManager) StartGame(game *models.Game) error {
 	if len(game.Players) != 2 {
@@ -75,23 +125,43 @@ func (egm *EnhancedGameManager) StartGame(game *models.Game) error {
 		player.LastManaUpdate = time.Now()
 	}
 	
+	game.BeginReplay()
+	
 	// Create enhanced game state
 	gameState := &EnhancedGameState{
 		Game:          game,
+		Arena:         NewArena(game, egm.battleEngine, egm.arenaConfig),
+		Hands:         make(map[string]*Hand),
 		StartTime:     time.Now(),
+		Phase:         PhaseRegular,
+		PhaseEnds:     time.Now().Add(time.Duration(egm.gameDuration) * time.Second),
 		LastManaUpdate: time.Now(),
 		GameEnded:     false,
+		done:          make(chan struct{}),
+	}
+	
+	// Deal each player a hand from their deck, in deck order
+	for _, player := range game.Players {
+		deck := make([]string, len(player.AvailableTroops))
+		for i, troop := range player.AvailableTroops {
+			deck[i] = troop.ID
+		}
+		gameState.Hands[player.ID] = NewHand(deck)
 	}
 	
 	// Start game timer
 	gameState.GameTimer = time.AfterFunc(time.Duration(egm.gameDuration)*time.Second, func() {
-		egm.endGameByTimeout(game.ID)
+		egm.timeUp(game.ID)
 	})
 	
 	// Start mana regeneration timer - Now correctly using Ticker
 	gameState.ManaTimer = time.NewTicker(time.Second)
 	go egm.manageManaRegeneration(gameState)
 	
+	// Start the arena simulation
+	gameState.TickTimer = time.NewTicker(egm.tickInterval)
+	go egm.runArena(gameState)
+	
 	// Store the game state
 	egm.mutex.Lock()
 	egm.activeGames[game.ID] = gameState
@@ -137,7 +207,7 @@ func (egm *EnhancedGameManager) ProcessAction(gameID, playerID string, action En
 	}
 	
 	// Update player's mana
-	player.UpdateMana(egm.manaRegenRate)
+	player.UpdateMana(egm.manaRegen(gameState))
 	
 	// Process the action
 	switch action.Type {
@@ -169,6 +239,16 @@ func (egm *EnhancedGameManager) processSpawnTroop(gameState *EnhancedGameState,
 		}, nil
 	}
 	
+	// Only cards in hand can be played
+	hand := gameState.Hands[player.ID]
+	if hand == nil || !hand.Has(troopTemplate.ID) {
+		return &EnhancedResult{
+			Success:    false,
+			Error:      "troop not in hand",
+			PlayerMana: player.Mana,
+		}, nil
+	}
+	
 	// Check mana cost
 	if !player.CanSpendMana(troopTemplate.ManaCost) {
 		return &EnhancedResult{
@@ -178,8 +258,8 @@ func (egm *EnhancedGameManager) processSpawnTroop(gameState *EnhancedGameState,
 		}, nil
 	}
 	
-	// Execute the attack
-	battleResult, err := egm.battleEngine.ExecuteAttack(gameState.Game, player.ID, action.TroopID, action.TargetTower)
+	// Pick the lane leading to the targeted tower
+	opponent, err := targetOpponent(gameState.Game, player.ID, action.TargetPlayer)
 	if err != nil {
 		return &EnhancedResult{
 			Success:    false,
@@ -188,63 +268,153 @@ func (egm *EnhancedGameManager) processSpawnTroop(gameState *EnhancedGameState,
 		}, nil
 	}
 	
-	// Calculate remaining time
-	timeLeft := egm.gameDuration - int(time.Since(gameState.StartTime).Seconds())
-	if timeLeft < 0 {
-		timeLeft = 0
+	lane, err := gameState.Arena.LaneForTarget(opponent, action.TargetTower)
+	if err != nil {
+		return &EnhancedResult{
+			Success:    false,
+			Error:      err.Error(),
+			PlayerMana: player.Mana,
+		}, nil
+	}
+	
+	// Spawn the troop; it fights as the arena ticks
+	manaBefore := player.Mana
+	unit, err := gameState.Arena.Spawn(player, troopTemplate, lane)
+	if err != nil {
+		return &EnhancedResult{
+			Success:    false,
+			Error:      err.Error(),
+			PlayerMana: player.Mana,
+		}, nil
 	}
+	player.SpendMana(troopTemplate.ManaCost)
+	hand.Play(troopTemplate.ID)
+	
+	gameState.Game.RecordAction(models.ReplayAction{
+		Kind:        models.ReplayEnhancedAction,
+		PlayerID:    player.ID,
+		Type:        action.Type,
+		TroopID:     action.TroopID,
+		TargetPlayer: opponent.ID,
+		TargetTower: action.TargetTower,
+		Lane:        lane,
+		Tick:        gameState.Arena.Tick(),
+		Mana:        manaBefore,
+		Timestamp:   action.Timestamp,
+	})
 	
-	result := &EnhancedResult{
+	unitState := unit.State()
+	return &EnhancedResult{
 		Success:      true,
-		BattleResult: battleResult,
+		Unit:         &unitState,
+		Hand:         hand.Held(),
 		PlayerMana:   player.Mana,
-		GameTimeLeft: timeLeft,
-		GameEnded:    battleResult.GameEnded,
+		GameTimeLeft: gameState.timeLeft(),
+	}, nil
+}
+
+// runArena advances the arena every tick until the game ends
+func (egm *EnhancedGameManager) runArena(gameState *EnhancedGameState) {
+	for {
+		select {
+		case <-gameState.done:
+			return
+		case <-gameState.TickTimer.C:
+		}
+		
+		if !egm.tick(gameState) {
+			return
+		}
+	}
+}
+
+// tick steps the arena once and ends the game if the step decided it. It
+// returns false once the game has ended. The tick is published before the
+// game ends, so game_ended is the last event of a game.
+func (egm *EnhancedGameManager) tick(gameState *EnhancedGameState) bool {
+	gameState.mutex.Lock()
+	defer gameState.mutex.Unlock()
+	if gameState.GameEnded {
+		return false
 	}
 	
-	// Check if game ended due to king tower destruction
-	if battleResult.GameEnded {
-		egm.endGame(gameState, battleResult.Winner)
-		result.Winner = battleResult.Winner
+	tick := gameState.Arena.Step()
+	egm.publishTick(gameState.Game, tick)
+	if tick.GameEnded {
+		egm.endGame(gameState, tick.Winner, "king_tower_destroyed")
+	} else if ended, winner := egm.checkEnd(gameState); ended {
+		egm.endGame(gameState, winner, "sudden_death")
+	}
+	return true
+}
+
+// checkEnd decides a game on a fallen king tower, or in sudden death on
+// the first tower destroyed. Callers hold the game state lock.
+func (egm *EnhancedGameManager) checkEnd(gameState *EnhancedGameState) (bool, string) {
+	game := gameState.Game
+	for _, player := range game.Players {
+		if king := player.KingTower(); king != nil && !king.IsAlive() {
+			return true, egm.battleEngine.GetGameWinner(game)
+		}
 	}
 	
-	return result, nil
+	// Sudden death starts tied, so any tower lost breaks the tie
+	if gameState.Phase == PhaseSuddenDeath {
+		if winner := egm.battleEngine.GetGameWinner(game); winner != "" {
+			return true, winner
+		}
+	}
+	return false, ""
+}
+
+func (egm *EnhancedGameManager) publishTick(game *models.Game, tick *ArenaTick) {
+	if egm.eventManager == nil {
+		return
+	}
+	
+	egm.eventManager.PublishArenaTick(game.ID, tick)
+	
+	for _, hit := range tick.Hits {
+		if hit.Destroyed && hit.TargetTower >= 0 {
+			egm.eventManager.PublishTowerDestroyed(game.ID, hit.TargetTower, hit.DefenderID, game.TeamOf(hit.DefenderID))
+		}
+	}
 }
 
 func (egm *EnhancedGameManager) manageManaRegeneration(gameState *EnhancedGameState) {
-	for range gameState.ManaTimer.C {
+	for {
+		select {
+		case <-gameState.done:
+			return
+		case <-gameState.ManaTimer.C:
+		}
+		
 		gameState.mutex.Lock()
 		if gameState.GameEnded {
 			gameState.mutex.Unlock()
 			break
 		}
 		
-		// Update mana for all players
+		// Update mana for all players, noting whose went up
+		changed := make(map[string]int)
 		for _, player := range gameState.Game.Players {
-			player.UpdateMana(egm.manaRegenRate)
+			before := player.Mana
+			player.UpdateMana(egm.manaRegen(gameState))
+			if player.Mana != before {
+				changed[player.ID] = player.Mana
+			}
 		}
 		gameState.mutex.Unlock()
+		
+		if egm.eventManager != nil {
+			for playerID, mana := range changed {
+				egm.eventManager.PublishManaUpdated(gameState.Game.ID, playerID, mana)
+			}
+		}
 	}
 }
 
-func (egm *EnhancedGameManager) endGameByTimeout(gameID string) {
-	egm.mutex.RLock()
-	gameState, exists := egm.activeGames[gameID]
-	egm.mutex.RUnlock()
-	
-	if !exists || gameState.GameEnded {
-		return
-	}
-	
-	gameState.mutex.Lock()
-	defer gameState.mutex.Unlock()
-	
-	// Determine winner based on tower destruction count
-	winner := egm.battleEngine.GetGameWinner(gameState.Game)
-	egm.endGame(gameState, winner)
-}
-
-func (egm *EnhancedGameManager) endGame(gameState *EnhancedGameState, winnerID string) {
+func (egm *EnhancedGameManager) endGame(gameState *EnhancedGameState, winnerID string, reason string) {
 	if gameState.GameEnded {
 		return
 	}
@@ -265,43 +435,44 @@ func (egm *EnhancedGameManager) endGame(gameState *EnhancedGameState, winnerID s
 	}
 	
 	// Stop timers
-	if gameState.GameTimer != nil {
-		gameState.GameTimer.Stop()
-	}
-	if gameState.ManaTimer != nil {
-		gameState.ManaTimer.Stop()
-	}
-	
-	// Award experience points
-	egm.awardExperience(gameState.Game)
+	gameState.stopTimers()
+	egm.battleEngine.RecordPlaces(gameState.Game)
 	
 	// Add end game event
 	gameState.Game.AddEvent("game_end", "", map[string]interface{}{
-		"reason": "time_up",
+		"reason": reason,
 		"winner": winnerID,
 	})
+	
+	gameState.Game.FinishReplay()
+	if gameState.Game.Replay != nil {
+		gameState.Game.Replay.Ticks = gameState.Arena.Tick()
+	}
+	if egm.eventManager != nil {
+		egm.eventManager.PublishGameEnded(gameState.Game, reason)
+	}
 }
 
-func (egm *EnhancedGameManager) awardExperience(game *models.Game) {
-	if len(game.Players) != 2 {
-		return
+// EndGame ends a running game early, deciding the winner on towers lost
+func (egm *EnhancedGameManager) EndGame(gameID string, reason string) error {
+	egm.mutex.RLock()
+	gameState, exists := egm.activeGames[gameID]
+	egm.mutex.RUnlock()
+	
+	if !exists {
+		return errors.New("game not found")
 	}
 	
-	player1 := game.Players[0]
-	player2 := game.Players[1]
+	gameState.mutex.Lock()
+	defer gameState.mutex.Unlock()
 	
-	if game.Winner == nil {
-		// Draw - both players get draw experience
-		player1.AddExperience(egm.expDraw)
-		player2.AddExperience(egm.expDraw)
-	} else {
-		// Winner gets win experience, loser gets 0
-		if game.Winner.ID == player1.ID {
-			player1.AddExperience(egm.expWin)
-		} else {
-			player2.AddExperience(egm.expWin)
-		}
+	if gameState.GameEnded {
+		return errors.New("game already finished")
 	}
+	
+	winner := egm.battleEngine.GetGameWinner(gameState.Game)
+	egm.endGame(gameState, winner, reason)
+	return nil
 }
 
 func (egm *EnhancedGameManager) GetGameState(gameID string) (map[string]interface{}, error) {
@@ -322,19 +493,23 @@ func (egm *EnhancedGameManager) GetGameState(gameID string) (map[string]interfac
 	state["mode"] = game.Mode
 	state["state"] = game.State
 	state["duration"] = game.Duration
-	
-	// Calculate remaining time
-	timeLeft := egm.gameDuration - int(time.Since(gameState.StartTime).Seconds())
-	if timeLeft < 0 {
-		timeLeft = 0
+	state["seed"] = game.Seed
+	state["balance_version"] = game.BalanceVersion
+	
+	// Remaining time is counted within the current phase
+	state["phase"] = gameState.Phase
+	state["time_remaining"] = gameState.timeLeft()
+	state["arena"] = map[string]interface{}{
+		"tick":        gameState.Arena.Tick(),
+		"lane_length": egm.arenaConfig.LaneLength,
+		"units":       gameState.Arena.Units(),
 	}
-	state["time_remaining"] = timeLeft
 	
 	// Player information
 	players := make([]map[string]interface{}, len(game.Players))
 	for i, player := range game.Players {
 		// Update mana
-		player.UpdateMana(egm.manaRegenRate)
+		player.UpdateMana(egm.manaRegen(gameState))
 		
 		playerData := map[string]interface{}{
 			"id":       player.ID,
@@ -344,6 +519,10 @@ func (egm *EnhancedGameManager) GetGameState(gameID string) (map[string]interfac
 			"towers":   egm.getTowerStates(player.Towers),
 			"troops":   egm.getAvailableTroops(player.AvailableTroops),
 		}
+		if hand, exists := gameState.Hands[player.ID]; exists {
+			playerData["hand"] = hand.Held()
+			playerData["next_card"] = hand.Next()
+		}
 		players[i] = playerData
 	}
 	state["players"] = players
@@ -358,8 +537,8 @@ func (egm *EnhancedGameManager) GetGameState(gameID string) (map[string]interfac
 		// Tower destruction counts
 		if len(game.Players) == 2 {
 			state["tower_scores"] = map[string]int{
-				game.Players[0].ID: 3 - egm.battleEngine.CountDestroyedTowers(game.Players[0]),
-				game.Players[1].ID: 3 - egm.battleEngine.CountDestroyedTowers(game.Players[1]),
+				game.Players[0].ID: len(game.Players[0].Towers) - egm.battleEngine.CountDestroyedTowers(game.Players[0]),
+				game.Players[1].ID: len(game.Players[1].Towers) - egm.battleEngine.CountDestroyedTowers(game.Players[1]),
 			}
 		}
 	}
@@ -393,6 +572,7 @@ func (egm *EnhancedGameManager) getAvailableTroops(troops []*models.Troop) []map
 			"hp":       troop.HP,
 			"mana_cost": troop.ManaCost,
 			"crit_chance": troop.CritChance,
+			"speed":     troop.Speed,
 		}
 	}
 	return states
@@ -405,14 +585,134 @@ func (egm *EnhancedGameManager) CleanupGame(gameID string) {
 	if gameState, exists := egm.activeGames[gameID]; exists {
 		gameState.mutex.Lock()
 		gameState.GameEnded = true
-		if gameState.GameTimer != nil {
-			gameState.GameTimer.Stop()
-		}
-		if gameState.ManaTimer != nil {
-			gameState.ManaTimer.Stop()
-		}
+		gameState.stopTimers()
 		gameState.mutex.Unlock()
 		
 		delete(egm.activeGames, gameID)
 	}
-}
\ No newline at end of file
+}
+func init() {
+	RegisterRuleSet(RuleSetDefinition{
+		Mode:     models.EnhancedMode,
+		Attack:   AttackRules{CriticalHits: true, SpendMana: true},
+		RealTime: true,
+		Experience: func(balance *Balance) ExperienceRewards {
+			return ExperienceRewards{Win: balance.Game.Enhanced.ExpWin, Draw: balance.Game.Enhanced.ExpDraw}
+		},
+		New: func(balance *Balance, events *EventManager) RuleSet {
+			manager := NewEnhancedGameManager(
+				balance.Game.Enhanced.ManaRegen,
+				balance.Game.Enhanced.GameDuration,
+				balance.Game.Enhanced.CritMultiplier,
+				balance.Game.Enhanced.TickInterval,
+			)
+			manager.SetOvertime(
+				balance.Game.Enhanced.OvertimeDuration,
+				balance.Game.Enhanced.OvertimeManaFactor,
+				balance.Game.Enhanced.SuddenDeathDuration,
+			)
+			manager.SetEventManager(events)
+			return manager
+		},
+	})
+}
+
+// ValidateAction decodes an EnhancedAction, stamped with the server's time
+func (egm *EnhancedGameManager) ValidateAction(game *models.Game, playerID string, payload json.RawMessage) (interface{}, error) {
+	var action EnhancedAction
+	if err := json.Unmarshal(payload, &action); err != nil {
+		return nil, errors.New("invalid action")
+	}
+	if action.Type != "spawn_troop" {
+		return nil, errors.New("invalid action type")
+	}
+	if action.TroopID == "" {
+		return nil, errors.New("troop_id is required")
+	}
+	action.Timestamp = time.Now()
+	return action, nil
+}
+
+// ApplyAction plays an EnhancedAction
+func (egm *EnhancedGameManager) ApplyAction(game *models.Game, playerID string, action interface{}) (ActionResult, error) {
+	enhanced, ok := action.(EnhancedAction)
+	if !ok {
+		return nil, fmt.Errorf("enhanced mode cannot play %T", action)
+	}
+	
+	result, err := egm.ProcessAction(game.ID, playerID, enhanced)
+	if err != nil {
+		return nil, err
+	}
+	if result.Success && egm.eventManager != nil {
+		egm.eventManager.PublishManaUpdated(game.ID, playerID, result.PlayerMana)
+	}
+	return result, nil
+}
+
+// State projects the battle, or the seated players of a game waiting to start
+func (egm *EnhancedGameManager) State(game *models.Game) (map[string]interface{}, error) {
+	if _, started := egm.gameState(game.ID); !started && game.State == models.Waiting {
+		players := make([]map[string]interface{}, len(game.Players))
+		for i, player := range game.Players {
+			players[i] = map[string]interface{}{
+				"id":       player.ID,
+				"username": player.Username,
+			}
+		}
+		return map[string]interface{}{
+			"mode":            game.Mode,
+			"state":           game.State,
+			"seed":            game.Seed,
+			"balance_version": game.BalanceVersion,
+			"players":         players,
+		}, nil
+	}
+	return egm.GetGameState(game.ID)
+}
+
+func (egm *EnhancedGameManager) End(game *models.Game, reason string) error {
+	return egm.EndGame(game.ID, reason)
+}
+
+// Forfeit ends the game with the opponent of a player who left as the winner
+func (egm *EnhancedGameManager) Forfeit(game *models.Game, playerID string, reason string) error {
+	gameState, exists := egm.gameState(game.ID)
+	if !exists {
+		return errors.New("game not found")
+	}
+	
+	gameState.mutex.Lock()
+	defer gameState.mutex.Unlock()
+	
+	if gameState.GameEnded {
+		return errors.New("game already finished")
+	}
+	
+	game.RecordAction(models.ReplayAction{
+		Kind:     models.ReplayEnhancedAction,
+		PlayerID: playerID,
+		Type:     "forfeit",
+		Tick:     gameState.Arena.Tick(),
+	})
+	_, winnerID := egm.battleEngine.Eliminate(game, playerID)
+	egm.endGame(gameState, winnerID, reason)
+	return nil
+}
+
+func (egm *EnhancedGameManager) gameState(gameID string) (*EnhancedGameState, bool) {
+	egm.mutex.RLock()
+	defer egm.mutex.RUnlock()
+	
+	gameState, exists := egm.activeGames[gameID]
+	return gameState, exists
+}
+
+// Accepted reports whether the action was played
+func (er *EnhancedResult) Accepted() bool {
+	return er.Success
+}
+
+func (er *EnhancedResult) Battle() *BattleResult {
+	return er.BattleResult
+}
@@ -18,24 +18,111 @@ const (
 	EventTowerDestroyed  EventType = "tower_destroyed"
 	EventGameEnded       EventType = "game_ended"
 	EventManaUpdated     EventType = "mana_updated"
+	EventArenaTick       EventType = "arena_tick"
+	EventMatchRewards    EventType = "match_rewards"
+	EventPhaseChanged    EventType = "phase_changed"
+	EventDraftUpdated    EventType = "draft_updated"
+	EventPlayerEliminated EventType = "player_eliminated"
+	EventActionResult    EventType = "action_result"
+	EventPlayerDisconnected EventType = "player_disconnected"
+	EventPlayerReconnected  EventType = "player_reconnected"
 )
 
+// GameEventData is one event of a game. Seq numbers a game's events in
+// the order they were published, starting from 1.
 type GameEventData struct {
 	Type      EventType   `json:"type"`
 	GameID    string      `json:"game_id"`
+	Seq       int64       `json:"seq"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"`
+	Game      *models.Game `json:"-"` // the game itself, for server-side handlers
 }
 
+// DefaultHistorySize is how many recent events are kept per game unless
+// set otherwise
+const DefaultHistorySize = 256
+
 type EventManager struct {
 	subscribers map[string][]chan GameEventData
+	handlers    map[EventType][]func(event GameEventData)
+	sequences   map[string]int64           // gameID -> seq of the latest event
+	history     map[string][]GameEventData // gameID -> most recent events
+	dispatching map[string]*sync.Mutex     // gameID -> held while an event is numbered and handled
+	holding     map[string]*sync.Mutex     // gameID -> held while an action is applied
+	held        map[string][]GameEventData // gameID -> events waiting for the action to be published
+	historySize int
 	mutex       sync.RWMutex
 }
 
 func NewEventManager() *EventManager {
 	return &EventManager{
 		subscribers: make(map[string][]chan GameEventData),
+		handlers:    make(map[EventType][]func(event GameEventData)),
+		sequences:   make(map[string]int64),
+		history:     make(map[string][]GameEventData),
+		dispatching: make(map[string]*sync.Mutex),
+		holding:     make(map[string]*sync.Mutex),
+		held:        make(map[string][]GameEventData),
+		historySize: DefaultHistorySize,
+	}
+}
+
+// SetHistorySize sets how many of each game's most recent events are kept
+// for clients catching up
+func (em *EventManager) SetHistorySize(size int) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+	
+	em.historySize = size
+	for gameID, events := range em.history {
+		if len(events) > size {
+			em.history[gameID] = events[len(events)-size:]
+		}
+	}
+}
+
+// LastSeq returns the sequence number of a game's latest event, or 0 if it
+// has none
+func (em *EventManager) LastSeq(gameID string) int64 {
+	em.mutex.RLock()
+	defer em.mutex.RUnlock()
+	
+	return em.sequences[gameID]
+}
+
+// Since returns a game's events published after seq, oldest first. It
+// reports false when some of them are no longer kept, or seq is one the
+// game has not reached.
+func (em *EventManager) Since(gameID string, seq int64) ([]GameEventData, bool) {
+	em.mutex.RLock()
+	defer em.mutex.RUnlock()
+	
+	last := em.sequences[gameID]
+	if seq < 0 || seq > last {
+		return nil, false
+	}
+	
+	events := em.history[gameID]
+	missed := int(last - seq)
+	if missed > len(events) {
+		return nil, false
 	}
+	
+	since := make([]GameEventData, missed)
+	copy(since, events[len(events)-missed:])
+	return since, true
+}
+
+// Handle registers a server-side handler for an event type across all
+// games. Unlike subscribers, handlers run synchronously and never miss an
+// event; each game's events reach them one at a time in seq order, so a
+// handler must not publish to the game it is handling.
+func (em *EventManager) Handle(eventType EventType, handler func(event GameEventData)) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+	
+	em.handlers[eventType] = append(em.handlers[eventType], handler)
 }
 
 func (em *EventManager) Subscribe(gameID string, ch chan GameEventData) {
@@ -64,10 +151,88 @@ func (em *EventManager) Unsubscribe(gameID string, ch chan GameEventData) {
 	}
 }
 
+// Publish numbers an event, keeps it in the game's history and passes it
+// to the handlers and subscribers. The game's next event waits until the
+// handlers are done with this one. While the game is held, the event waits
+// for the release instead.
 func (em *EventManager) Publish(event GameEventData) {
-	em.mutex.RLock()
+	em.mutex.Lock()
+	if queue, held := em.held[event.GameID]; held {
+		em.held[event.GameID] = append(queue, event)
+		em.mutex.Unlock()
+		return
+	}
+	em.mutex.Unlock()
+	
+	em.publish(event)
+}
+
+// Hold keeps a game's events back while an action is applied, so the
+// turn change or game end it causes cannot reach clients before the action
+// itself. The returned release publishes the given events first, then the
+// ones held back, in the order they came. A game is held by one action at
+// a time.
+func (em *EventManager) Hold(gameID string) func(first ...GameEventData) {
+	em.mutex.Lock()
+	holding, exists := em.holding[gameID]
+	if !exists {
+		holding = &sync.Mutex{}
+		em.holding[gameID] = holding
+	}
+	em.mutex.Unlock()
+	
+	holding.Lock()
+	em.mutex.Lock()
+	em.held[gameID] = []GameEventData{}
+	em.mutex.Unlock()
+	
+	return func(first ...GameEventData) {
+		defer holding.Unlock()
+		
+		for _, event := range first {
+			em.publish(event)
+		}
+		for {
+			em.mutex.Lock()
+			queue := em.held[gameID]
+			if len(queue) == 0 {
+				delete(em.held, gameID)
+				em.mutex.Unlock()
+				return
+			}
+			em.held[gameID] = []GameEventData{}
+			em.mutex.Unlock()
+			
+			for _, event := range queue {
+				em.publish(event)
+			}
+		}
+	}
+}
+
+// publish numbers and dispatches an event whether or not its game is held
+func (em *EventManager) publish(event GameEventData) {
+	dispatching := em.dispatchLock(event.GameID)
+	dispatching.Lock()
+	defer dispatching.Unlock()
+	
+	em.mutex.Lock()
+	em.sequences[event.GameID]++
+	event.Seq = em.sequences[event.GameID]
+	if em.historySize > 0 {
+		events := append(em.history[event.GameID], event)
+		if len(events) > em.historySize {
+			events = events[len(events)-em.historySize:]
+		}
+		em.history[event.GameID] = events
+	}
 	subscribers := em.subscribers[event.GameID]
-	em.mutex.RUnlock()
+	handlers := em.handlers[event.Type]
+	em.mutex.Unlock()
+	
+	for _, handler := range handlers {
+		handler(event)
+	}
 	
 	if len(subscribers) == 0 {
 		return
@@ -90,7 +255,21 @@ func (em *EventManager) Publish(event GameEventData) {
 	}
 }
 
-func (em *EventManager) PublishPlayerJoined(gameID string, player *models.Player) {
+// dispatchLock returns the lock a game's events are published under
+func (em *EventManager) dispatchLock(gameID string) *sync.Mutex {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+	
+	dispatching, exists := em.dispatching[gameID]
+	if !exists {
+		dispatching = &sync.Mutex{}
+		em.dispatching[gameID] = dispatching
+	}
+	return dispatching
+}
+
+// PublishPlayerJoined announces a player taking a seat and the side they play on
+func (em *EventManager) PublishPlayerJoined(gameID string, player *models.Player, team int) {
 	em.Publish(GameEventData{
 		Type:      EventPlayerJoined,
 		GameID:    gameID,
@@ -98,6 +277,35 @@ func (em *EventManager) PublishPlayerJoined(gameID string, player *models.Player
 		Data: map[string]interface{}{
 			"player_id": player.ID,
 			"username":  player.Username,
+			"team":      team,
+		},
+	})
+}
+
+// PublishPlayerDisconnected announces a player's last connection to a game
+// closing and the seconds they have to come back, 0 if they cannot forfeit
+func (em *EventManager) PublishPlayerDisconnected(gameID, playerID string, team, graceSeconds int) {
+	em.Publish(GameEventData{
+		Type:      EventPlayerDisconnected,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"player_id":     playerID,
+			"team":          team,
+			"grace_seconds": graceSeconds,
+		},
+	})
+}
+
+// PublishPlayerReconnected announces a player who was away coming back
+func (em *EventManager) PublishPlayerReconnected(gameID, playerID string, team int) {
+	em.Publish(GameEventData{
+		Type:      EventPlayerReconnected,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"player_id": playerID,
+			"team":      team,
 		},
 	})
 }
@@ -110,54 +318,149 @@ func (em *EventManager) PublishGameStarted(gameID string, game *models.Game) {
 		Data: map[string]interface{}{
 			"mode":    game.Mode,
 			"players": len(game.Players),
+			"state":   game.State,
 		},
 	})
 }
 
-func (em *EventManager) PublishTurnChanged(gameID string, currentPlayerID string) {
+// PublishTurnChanged announces whose turn it is, their side and how long
+// they have. Reason is "start", "action", "timeout", "forfeit" or "resume",
+// when a paused clock restarts with the time the turn had left.
+func (em *EventManager) PublishTurnChanged(gameID string, currentPlayerID string, team, turnSeconds, timeouts int, reason string) {
 	em.Publish(GameEventData{
 		Type:      EventTurnChanged,
 		GameID:    gameID,
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
-			"current_player": currentPlayerID,
+			"current_player":         currentPlayerID,
+			"team":                   team,
+			"turn_remaining_seconds": turnSeconds,
+			"timeouts":               timeouts,
+			"reason":                 reason,
 		},
 	})
 }
 
-func (em *EventManager) PublishAttackMade(gameID string, result *BattleResult) {
-	em.Publish(GameEventData{
+// ActionEvents returns the events announcing an action a player sent: its
+// result, whether or not the rules accepted it, and the attack it made and
+// the tower it destroyed, if any
+func ActionEvents(gameID, playerID string, result ActionResult) []GameEventData {
+	events := []GameEventData{{
+		Type:      EventActionResult,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"player_id": playerID,
+			"result":    result,
+		},
+	}}
+	
+	battle := result.Battle()
+	if !result.Accepted() || battle == nil {
+		return events
+	}
+	events = append(events, GameEventData{
 		Type:      EventAttackMade,
 		GameID:    gameID,
 		Timestamp: time.Now(),
-		Data:      result,
+		Data:      battle,
 	})
+	if battle.TowerDestroyed {
+		events = append(events, towerDestroyedEvent(gameID, battle.TargetTower, battle.DefenderID, battle.DefenderTeam))
+	}
+	return events
 }
 
-func (em *EventManager) PublishTowerDestroyed(gameID string, towerIndex int, playerID string) {
-	em.Publish(GameEventData{
+func (em *EventManager) PublishTowerDestroyed(gameID string, towerIndex int, playerID string, team int) {
+	em.Publish(towerDestroyedEvent(gameID, towerIndex, playerID, team))
+}
+
+func towerDestroyedEvent(gameID string, towerIndex int, playerID string, team int) GameEventData {
+	return GameEventData{
 		Type:      EventTowerDestroyed,
 		GameID:    gameID,
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
 			"tower_index": towerIndex,
 			"player_id":   playerID,
+			"team":        team,
+		},
+	}
+}
+
+// PublishPlayerEliminated announces a player knocked out of a game and the
+// place they finished in. Reason is "king_tower_destroyed", "forfeit" or
+// "abandoned", for a player who disconnected and did not return.
+func (em *EventManager) PublishPlayerEliminated(gameID, playerID string, team, place int, reason string) {
+	em.Publish(GameEventData{
+		Type:      EventPlayerEliminated,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"player_id": playerID,
+			"team":      team,
+			"place":     place,
+			"reason":    reason,
 		},
 	})
 }
 
-func (em *EventManager) PublishGameEnded(gameID string, winner string, reason string) {
+func (em *EventManager) PublishGameEnded(game *models.Game, reason string) {
+	winner := ""
+	winnerTeam := -1
+	if game.Winner != nil {
+		winner = game.Winner.ID
+		winnerTeam = game.TeamOf(winner)
+	}
+	
 	em.Publish(GameEventData{
 		Type:      EventGameEnded,
+		GameID:    game.ID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"winner":      winner,
+			"winner_team": winnerTeam,
+			"places":      game.Places,
+			"reason":      reason,
+		},
+		Game: game,
+	})
+}
+
+// PublishPhaseChanged announces that a tied enhanced game went to overtime or sudden death
+func (em *EventManager) PublishPhaseChanged(gameID string, phase MatchPhase, seconds int, manaRegen float64) {
+	em.Publish(GameEventData{
+		Type:      EventPhaseChanged,
 		GameID:    gameID,
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
-			"winner": winner,
-			"reason": reason,
+			"phase":                  phase,
+			"time_remaining_seconds": seconds,
+			"mana_regen_per_second":  manaRegen,
 		},
 	})
 }
 
+// PublishDraftUpdated announces a pick or ban and whose step is next. The
+// last update of a draft has "complete" set.
+func (em *EventManager) PublishDraftUpdated(gameID string, draft map[string]interface{}) {
+	em.Publish(GameEventData{
+		Type:      EventDraftUpdated,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data:      draft,
+	})
+}
+
+func (em *EventManager) PublishMatchRewards(gameID string, rewards map[string]*MatchRewards) {
+	em.Publish(GameEventData{
+		Type:      EventMatchRewards,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data:      rewards,
+	})
+}
+
 func (em *EventManager) PublishManaUpdated(gameID string, playerID string, mana int) {
 	em.Publish(GameEventData{
 		Type:      EventManaUpdated,
@@ -170,11 +473,26 @@ func (em *EventManager) PublishManaUpdated(gameID string, playerID string, mana
 	})
 }
 
-// CleanupGame removes all subscribers for a specific game
+func (em *EventManager) PublishArenaTick(gameID string, tick *ArenaTick) {
+	em.Publish(GameEventData{
+		Type:      EventArenaTick,
+		GameID:    gameID,
+		Timestamp: time.Now(),
+		Data:      tick,
+	})
+}
+
+// CleanupGame removes all subscribers and the event history of a specific game
 func (em *EventManager) CleanupGame(gameID string) {
 	em.mutex.Lock()
 	defer em.mutex.Unlock()
 	
+	delete(em.sequences, gameID)
+	delete(em.history, gameID)
+	delete(em.dispatching, gameID)
+	delete(em.holding, gameID)
+	delete(em.held, gameID)
+	
 	// Close all channels for this game
 	if subscribers, exists := em.subscribers[gameID]; exists {
 		for _, ch := range subscribers {
@@ -0,0 +1,35 @@
+// internal/game/ffa.go - Free-for-all matches
+package game
+
+import "tcr-game/internal/models"
+
+// Free-for-all matches seat 3 or 4 players, each with their own towers.
+// Turns go round the table under the simple mode rules, and every attack
+// names the player it targets. A player whose king tower falls is knocked
+// out and takes the lowest place left; the last player standing wins.
+func init() {
+	RegisterRuleSet(RuleSetDefinition{
+		Mode:       models.FFAMode,
+		Attack:     AttackRules{OrderedTargets: true, Retaliation: true, ContinueOnKill: true},
+		Players:    4,
+		MinPlayers: 3,
+		Experience: func(balance *Balance) ExperienceRewards {
+			return ExperienceRewards{Win: balance.Game.FFA.ExpWin, Draw: balance.Game.FFA.ExpDraw}
+		},
+		New: func(balance *Balance, events *EventManager) RuleSet {
+			turnTime := balance.Game.FFA.TurnTime
+			if turnTime <= 0 {
+				turnTime = balance.Game.Simple.TurnTime
+			}
+
+			manager := NewSimpleGameManager(
+				4,
+				turnTime,
+				balance.Game.Simple.MaxTimeouts,
+				balance.Game.Enhanced.CritMultiplier,
+			)
+			manager.SetEventManager(events)
+			return manager
+		},
+	})
+}
@@ -0,0 +1,69 @@
+// internal/game/hand.go - Cycling hand of deck cards for enhanced mode
+package game
+
+import "tcr-game/internal/models"
+
+// Hand holds the cards a player can play right now and the rest of their
+// deck in the order it will be drawn. A played card goes to the back of the
+// queue and the front card of the queue takes its slot.
+type Hand struct {
+	Cards []string `json:"cards"`
+	Queue []string `json:"queue"`
+}
+
+// NewHand deals the first HandSize cards of the deck
+func NewHand(deck []string) *Hand {
+	size := models.HandSize
+	if len(deck) < size {
+		size = len(deck)
+	}
+
+	hand := &Hand{
+		Cards: make([]string, size),
+		Queue: make([]string, len(deck)-size),
+	}
+	copy(hand.Cards, deck[:size])
+	copy(hand.Queue, deck[size:])
+	return hand
+}
+
+// Has reports whether a card is in the hand
+func (h *Hand) Has(troopID string) bool {
+	return h.slot(troopID) >= 0
+}
+
+// Play cycles a card out of the hand. It returns false if the card is not in it.
+func (h *Hand) Play(troopID string) bool {
+	slot := h.slot(troopID)
+	if slot < 0 {
+		return false
+	}
+
+	h.Queue = append(h.Queue, troopID)
+	h.Cards[slot] = h.Queue[0]
+	h.Queue = h.Queue[1:]
+	return true
+}
+
+// Held returns a copy of the cards in the hand, which stays the same as
+// play goes on
+func (h *Hand) Held() []string {
+	return append([]string(nil), h.Cards...)
+}
+
+// Next returns the card that will be drawn after the next play
+func (h *Hand) Next() string {
+	if len(h.Queue) == 0 {
+		return ""
+	}
+	return h.Queue[0]
+}
+
+func (h *Hand) slot(troopID string) int {
+	for i, card := range h.Cards {
+		if card == troopID {
+			return i
+		}
+	}
+	return -1
+}
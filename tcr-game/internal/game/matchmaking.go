@@ -0,0 +1,328 @@
+// internal/game/matchmaking.go - Skill-based matchmaking queue
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/models"
+)
+
+type QueueState string
+
+const (
+	QueueIdle    QueueState = "idle"
+	QueueWaiting QueueState = "waiting"
+	QueueMatched QueueState = "matched"
+)
+
+type queueEntry struct {
+	player   *models.Player
+	mode     models.GameMode
+	rating   int
+	joinedAt time.Time
+}
+
+// QueueStatus is a player's view of the matchmaking queue
+type QueueStatus struct {
+	State       QueueState      `json:"state"`
+	Mode        models.GameMode `json:"mode,omitempty"`
+	Position    int             `json:"position,omitempty"` // 1-based place in the mode's queue
+	QueueSize   int             `json:"queue_size,omitempty"`
+	WaitSeconds int             `json:"wait_seconds,omitempty"`
+	Window      int             `json:"window,omitempty"` // rating distance currently accepted
+	GameID      string          `json:"game_id,omitempty"`
+}
+
+// Match is a game created for two queued players
+type Match struct {
+	GameID  string           `json:"game_id"`
+	Mode    models.GameMode  `json:"mode"`
+	Players []*models.Player `json:"players"`
+}
+
+type Matchmaker struct {
+	engine  *GameEngine
+	config  config.MatchmakingConfig
+	queues  map[models.GameMode][]*queueEntry
+	entries map[string]*queueEntry // player ID -> entry
+	matched map[string]string      // player ID -> game ID of their last match
+	onMatch func(match *Match)
+	created int
+	mutex   sync.Mutex
+}
+
+func NewMatchmaker(engine *GameEngine, cfg config.MatchmakingConfig) *Matchmaker {
+	return &Matchmaker{
+		engine:  engine,
+		config:  cfg,
+		queues:  make(map[models.GameMode][]*queueEntry),
+		entries: make(map[string]*queueEntry),
+		matched: make(map[string]string),
+	}
+}
+
+// SetMatchHandler registers a callback invoked for every game the matchmaker creates
+func (mm *Matchmaker) SetMatchHandler(handler func(match *Match)) {
+	mm.onMatch = handler
+}
+
+// Enqueue adds a player to the queue of a mode
+func (mm *Matchmaker) Enqueue(player *models.Player, mode models.GameMode, now time.Time) (*QueueStatus, error) {
+	if !IsRegisteredMode(mode) {
+		return nil, errors.New("invalid game mode")
+	}
+	if playersFor(mode) != 2 {
+		return nil, errors.New("matchmaking only pairs two-player modes")
+	}
+
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	if _, queued := mm.entries[player.ID]; queued {
+		return nil, errors.New("player already queued")
+	}
+
+	entry := &queueEntry{
+		player:   player,
+		mode:     mode,
+		rating:   mm.ratingOf(player, mode),
+		joinedAt: now,
+	}
+	mm.queues[mode] = append(mm.queues[mode], entry)
+	mm.entries[player.ID] = entry
+	delete(mm.matched, player.ID)
+
+	return mm.statusLocked(player.ID, now), nil
+}
+
+// Cancel removes a player from the queue
+func (mm *Matchmaker) Cancel(playerID string) error {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	entry, queued := mm.entries[playerID]
+	if !queued {
+		return errors.New("player not queued")
+	}
+
+	mm.removeLocked(entry)
+	return nil
+}
+
+func (mm *Matchmaker) Status(playerID string, now time.Time) *QueueStatus {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	return mm.statusLocked(playerID, now)
+}
+
+func (mm *Matchmaker) statusLocked(playerID string, now time.Time) *QueueStatus {
+	if gameID, exists := mm.matched[playerID]; exists {
+		return &QueueStatus{State: QueueMatched, GameID: gameID}
+	}
+
+	entry, queued := mm.entries[playerID]
+	if !queued {
+		return &QueueStatus{State: QueueIdle}
+	}
+
+	queue := mm.queues[entry.mode]
+	position := 0
+	for i, queuedEntry := range queue {
+		if queuedEntry == entry {
+			position = i + 1
+			break
+		}
+	}
+
+	return &QueueStatus{
+		State:       QueueWaiting,
+		Mode:        entry.mode,
+		Position:    position,
+		QueueSize:   len(queue),
+		WaitSeconds: int(now.Sub(entry.joinedAt).Seconds()),
+		Window:      mm.window(entry, now),
+	}
+}
+
+// Start pairs queued players every configured interval until stop is closed
+func (mm *Matchmaker) Start() chan struct{} {
+	stop := make(chan struct{})
+	interval := time.Duration(mm.config.Interval) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				mm.MatchPlayers(now)
+			}
+		}
+	}()
+
+	return stop
+}
+
+// MatchPlayers pairs every player it can, longest waiting first, and
+// creates a game for each pair
+func (mm *Matchmaker) MatchPlayers(now time.Time) []*Match {
+	pairs := mm.pair(now)
+
+	matches := make([]*Match, 0, len(pairs))
+	for _, pair := range pairs {
+		match, err := mm.createMatch(pair[0], pair[1])
+		if err != nil {
+			log.Printf("Failed to create match for %s and %s: %v", pair[0].player.ID, pair[1].player.ID, err)
+			mm.requeue(pair[0], pair[1])
+			continue
+		}
+		matches = append(matches, match)
+
+		if mm.onMatch != nil {
+			mm.onMatch(match)
+		}
+	}
+
+	return matches
+}
+
+// pair takes matching pairs out of the queues. A pair is accepted when the
+// rating difference fits the window of the player who has waited longer.
+func (mm *Matchmaker) pair(now time.Time) [][2]*queueEntry {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	pairs := make([][2]*queueEntry, 0)
+	for _, queue := range mm.queues {
+		taken := make(map[*queueEntry]bool)
+
+		for i, entry := range queue {
+			if taken[entry] {
+				continue
+			}
+
+			window := mm.window(entry, now)
+			var best *queueEntry
+			for _, candidate := range queue[i+1:] {
+				if taken[candidate] {
+					continue
+				}
+				distance := abs(entry.rating - candidate.rating)
+				if distance > window {
+					continue
+				}
+				if best == nil || distance < abs(entry.rating-best.rating) {
+					best = candidate
+				}
+			}
+
+			if best != nil {
+				taken[entry] = true
+				taken[best] = true
+				pairs = append(pairs, [2]*queueEntry{entry, best})
+			}
+		}
+	}
+
+	for _, pair := range pairs {
+		mm.removeLocked(pair[0])
+		mm.removeLocked(pair[1])
+	}
+
+	return pairs
+}
+
+func (mm *Matchmaker) createMatch(first, second *queueEntry) (*Match, error) {
+	mm.mutex.Lock()
+	mm.created++
+	gameID := fmt.Sprintf("match_%d_%d", time.Now().UnixNano(), mm.created)
+	mm.mutex.Unlock()
+
+	if _, err := mm.engine.CreateGame(gameID, first.mode); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range []*queueEntry{first, second} {
+		if err := mm.engine.JoinGame(gameID, entry.player); err != nil {
+			mm.engine.CleanupGame(gameID)
+			return nil, err
+		}
+	}
+
+	mm.mutex.Lock()
+	mm.matched[first.player.ID] = gameID
+	mm.matched[second.player.ID] = gameID
+	mm.mutex.Unlock()
+
+	return &Match{
+		GameID:  gameID,
+		Mode:    first.mode,
+		Players: []*models.Player{first.player, second.player},
+	}, nil
+}
+
+// requeue puts the players of a failed match back at their old place in line
+func (mm *Matchmaker) requeue(entries ...*queueEntry) {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	for _, entry := range entries {
+		if _, queued := mm.entries[entry.player.ID]; queued {
+			continue
+		}
+		queue := append(mm.queues[entry.mode], entry)
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].joinedAt.Before(queue[j].joinedAt)
+		})
+		mm.queues[entry.mode] = queue
+		mm.entries[entry.player.ID] = entry
+	}
+}
+
+func (mm *Matchmaker) removeLocked(entry *queueEntry) {
+	queue := mm.queues[entry.mode]
+	for i, queuedEntry := range queue {
+		if queuedEntry == entry {
+			mm.queues[entry.mode] = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	delete(mm.entries, entry.player.ID)
+}
+
+// window is the rating distance a player accepts after waiting since joinedAt
+func (mm *Matchmaker) window(entry *queueEntry, now time.Time) int {
+	waited := int(now.Sub(entry.joinedAt).Seconds())
+	window := mm.config.InitialWindow + waited*mm.config.WindowGrowth
+	if mm.config.MaxWindow > 0 && window > mm.config.MaxWindow {
+		window = mm.config.MaxWindow
+	}
+	return window
+}
+
+func (mm *Matchmaker) ratingOf(player *models.Player, mode models.GameMode) int {
+	if rating, exists := player.Ratings[mode]; exists {
+		return rating.Value
+	}
+	return models.DefaultRating
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
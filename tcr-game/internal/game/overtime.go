@@ -0,0 +1,136 @@
+// internal/game/overtime.go - Overtime and sudden death for tied enhanced games
+package game
+
+import (
+	"time"
+
+	"tcr-game/internal/models"
+)
+
+// MatchPhase is the period an enhanced game is being played in
+type MatchPhase string
+
+const (
+	PhaseRegular     MatchPhase = "regular"
+	PhaseOvertime    MatchPhase = "overtime"
+	PhaseSuddenDeath MatchPhase = "sudden_death"
+)
+
+// SetOvertime enables overtime rules. A game tied when regular time runs
+// out plays overtime with mana regenerating manaFactor times faster, then
+// sudden death where the first tower destroyed wins. A period of 0 seconds
+// is skipped. If both are still tied, the player whose weakest tower has
+// the lower HP percentage loses.
+func (egm *EnhancedGameManager) SetOvertime(overtimeSeconds int, manaFactor float64, suddenDeathSeconds int) {
+	if manaFactor < 1 {
+		manaFactor = 1
+	}
+	egm.overtime = overtimeSeconds
+	egm.overtimeManaFactor = manaFactor
+	egm.suddenDeath = suddenDeathSeconds
+}
+
+func (egm *EnhancedGameManager) overtimeEnabled() bool {
+	return egm.overtime > 0 || egm.suddenDeath > 0
+}
+
+// manaRegen is the mana regeneration rate of the game's current phase
+func (egm *EnhancedGameManager) manaRegen(gameState *EnhancedGameState) float64 {
+	if gameState.Phase == PhaseRegular {
+		return egm.manaRegenRate
+	}
+	return egm.manaRegenRate * egm.overtimeManaFactor
+}
+
+// timeUp runs when a phase's clock runs out. A game with a leader on
+// towers ends; a tied game moves to the next phase, or is decided by the
+// tiebreaker once every phase is over.
+func (egm *EnhancedGameManager) timeUp(gameID string) {
+	egm.mutex.RLock()
+	gameState, exists := egm.activeGames[gameID]
+	egm.mutex.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	gameState.mutex.Lock()
+	defer gameState.mutex.Unlock()
+
+	if gameState.GameEnded {
+		return
+	}
+
+	if winner := egm.battleEngine.GetGameWinner(gameState.Game); winner != "" || !egm.overtimeEnabled() {
+		egm.endGame(gameState, winner, "time_up")
+		return
+	}
+
+	switch {
+	case gameState.Phase == PhaseRegular && egm.overtime > 0:
+		egm.enterPhase(gameState, PhaseOvertime, egm.overtime)
+	case gameState.Phase != PhaseSuddenDeath && egm.suddenDeath > 0:
+		egm.enterPhase(gameState, PhaseSuddenDeath, egm.suddenDeath)
+	default:
+		if gameState.Game.Replay != nil {
+			gameState.Game.Replay.Tiebreaker = true
+		}
+		egm.endGame(gameState, egm.battleEngine.TiebreakWinner(gameState.Game), "tiebreaker")
+	}
+}
+
+// enterPhase starts a new phase's clock and announces it. Callers hold the
+// game state lock.
+func (egm *EnhancedGameManager) enterPhase(gameState *EnhancedGameState, phase MatchPhase, seconds int) {
+	// Mana earned so far is credited at the old rate
+	for _, player := range gameState.Game.Players {
+		player.UpdateMana(egm.manaRegen(gameState))
+	}
+
+	gameState.Phase = phase
+	gameState.PhaseEnds = time.Now().Add(time.Duration(seconds) * time.Second)
+	gameState.GameTimer = time.AfterFunc(time.Duration(seconds)*time.Second, func() {
+		egm.timeUp(gameState.Game.ID)
+	})
+
+	if phase == PhaseSuddenDeath && gameState.Game.Replay != nil {
+		gameState.Game.Replay.SuddenDeathTick = gameState.Arena.Tick()
+	}
+
+	if egm.eventManager != nil {
+		egm.eventManager.PublishPhaseChanged(gameState.Game.ID, phase, seconds, egm.manaRegen(gameState))
+	}
+}
+
+// TiebreakWinner decides a game still tied after overtime: the player
+// whose weakest standing tower has the lower HP percentage loses. Equal
+// percentages are a draw.
+func (be *BattleEngine) TiebreakWinner(game *models.Game) string {
+	if len(game.Players) != 2 {
+		return ""
+	}
+
+	first := weakestTowerPercent(game.Players[0])
+	second := weakestTowerPercent(game.Players[1])
+	switch {
+	case first > second:
+		return game.Players[0].ID
+	case second > first:
+		return game.Players[1].ID
+	default:
+		return ""
+	}
+}
+
+func weakestTowerPercent(player *models.Player) float64 {
+	weakest := 1.0
+	for _, tower := range player.Towers {
+		if tower == nil || !tower.IsAlive() || tower.MaxHP <= 0 {
+			continue
+		}
+		if percent := float64(tower.HP) / float64(tower.MaxHP); percent < weakest {
+			weakest = percent
+		}
+	}
+	return weakest
+}
@@ -0,0 +1,216 @@
+// internal/game/presence.go - Which players of a game are connected
+package game
+
+import (
+	"log"
+	"time"
+
+	"tcr-game/internal/models"
+)
+
+// gamePresence counts the open connections of each player of a game. A
+// seated player whose last connection closes is away until they return.
+type gamePresence struct {
+	connections map[string]int
+	away        map[string]*absence
+}
+
+// absence is a player away from a game being played. They forfeit when
+// the timer fires at the deadline; games without a grace period set none.
+type absence struct {
+	timer    *time.Timer
+	deadline time.Time
+}
+
+// presenceOf returns a game's presence, creating it. Callers hold the
+// presence lock.
+func (ge *GameEngine) presenceOf(gameID string) *gamePresence {
+	presence, exists := ge.presence[gameID]
+	if !exists {
+		presence = &gamePresence{
+			connections: make(map[string]int),
+			away:        make(map[string]*absence),
+		}
+		ge.presence[gameID] = presence
+	}
+	return presence
+}
+
+// PlayerConnected records a connection of a player to a game. A player
+// coming back while away is announced to the others, and a clock paused
+// for them restarts once nobody is away. Presence is only kept for the
+// players of games that exist and have not finished.
+func (ge *GameEngine) PlayerConnected(gameID, playerID string) {
+	ge.presenceMutex.Lock()
+	defer ge.presenceMutex.Unlock()
+
+	game, err := ge.GetGame(gameID)
+	if err != nil || game.State == models.Finished || game.TeamOf(playerID) < 0 {
+		return
+	}
+	presence := ge.presenceOf(gameID)
+	presence.connections[playerID]++
+
+	away, wasAway := presence.away[playerID]
+	if !wasAway {
+		return
+	}
+	if away.timer != nil {
+		away.timer.Stop()
+	}
+	delete(presence.away, playerID)
+
+	ge.events.PublishPlayerReconnected(gameID, playerID, game.TeamOf(playerID))
+	if away.timer != nil && !ge.pausedFor(presence) {
+		ge.resume(game)
+	}
+}
+
+// PlayerDisconnected records a connection of a player to a game closing.
+// When it was their last, the others are told. In a game being played
+// the player has the grace period to come back: the turn clock waits for
+// them, and they forfeit if they do not return in time.
+func (ge *GameEngine) PlayerDisconnected(gameID, playerID string) {
+	ge.presenceMutex.Lock()
+	defer ge.presenceMutex.Unlock()
+
+	presence, exists := ge.presence[gameID]
+	if !exists || presence.connections[playerID] == 0 {
+		return
+	}
+	presence.connections[playerID]--
+	if presence.connections[playerID] > 0 {
+		return
+	}
+	delete(presence.connections, playerID)
+
+	game, err := ge.GetGame(gameID)
+	if err != nil || game.State == models.Finished || game.TeamOf(playerID) < 0 || game.PlaceOf(playerID) > 0 {
+		return
+	}
+
+	grace := 0
+	if game.State == models.InProgress || game.State == models.Drafting {
+		grace = ge.rulesFor(game).balance.Game.DisconnectGrace
+	}
+
+	pause := grace > 0 && !ge.pausedFor(presence)
+	away := &absence{}
+	if grace > 0 {
+		duration := time.Duration(grace) * time.Second
+		away.deadline = time.Now().Add(duration)
+		away.timer = time.AfterFunc(duration, func() {
+			ge.abandoned(game, playerID, away)
+		})
+	}
+	presence.away[playerID] = away
+
+	ge.events.PublishPlayerDisconnected(gameID, playerID, game.TeamOf(playerID), grace)
+	if pause {
+		if pauser, ok := ge.pauser(game); ok {
+			pauser.Pause(game)
+		}
+	}
+}
+
+// abandoned forfeits a player who did not come back within the grace
+// period. The presence lock is not held while the rule set forfeits them,
+// since ending the game forgets the game's presence.
+func (ge *GameEngine) abandoned(game *models.Game, playerID string, away *absence) {
+	ge.presenceMutex.Lock()
+	presence, exists := ge.presence[game.ID]
+	if !exists || presence.away[playerID] != away {
+		// The player came back, or the game ended or was cleaned up
+		ge.presenceMutex.Unlock()
+		return
+	}
+	away.timer = nil
+	ge.presenceMutex.Unlock()
+
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return
+	}
+	if forfeiter, ok := ruleSet.(Forfeiter); ok {
+		err = forfeiter.Forfeit(game, playerID, "abandoned")
+	} else {
+		err = ruleSet.End(game, "abandoned")
+	}
+	if err != nil {
+		log.Printf("Failed to forfeit %s from game %s: %v", playerID, game.ID, err)
+	}
+
+	// The others play on once nobody else is being waited for
+	ge.presenceMutex.Lock()
+	defer ge.presenceMutex.Unlock()
+	if presence, exists := ge.presence[game.ID]; exists && !ge.pausedFor(presence) {
+		ge.resume(game)
+	}
+}
+
+// pausedFor reports whether a game's clock is waiting for an away player.
+// Callers hold the presence lock.
+func (ge *GameEngine) pausedFor(presence *gamePresence) bool {
+	for _, away := range presence.away {
+		if away.timer != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (ge *GameEngine) resume(game *models.Game) {
+	if pauser, ok := ge.pauser(game); ok {
+		pauser.Resume(game)
+	}
+}
+
+// pauser returns the rule set of a game if its clock can be paused
+func (ge *GameEngine) pauser(game *models.Game) (Pauser, bool) {
+	ruleSet, err := ge.ruleSet(game)
+	if err != nil {
+		return nil, false
+	}
+	pauser, ok := ruleSet.(Pauser)
+	return pauser, ok
+}
+
+// presenceState projects whether each player of a game is connected, and
+// how long those away have left to come back
+func (ge *GameEngine) presenceState(game *models.Game) map[string]interface{} {
+	ge.presenceMutex.Lock()
+	defer ge.presenceMutex.Unlock()
+
+	presence := ge.presence[game.ID]
+	state := make(map[string]interface{}, len(game.Players))
+	for _, player := range game.Players {
+		playerState := map[string]interface{}{"connected": false}
+		if presence != nil {
+			playerState["connected"] = presence.connections[player.ID] > 0
+			if away, exists := presence.away[player.ID]; exists {
+				playerState["away"] = true
+				if away.timer != nil {
+					playerState["forfeit_in"] = wholeSeconds(time.Until(away.deadline))
+				}
+			}
+		}
+		state[player.ID] = playerState
+	}
+	return state
+}
+
+// forgetPresence stops the forfeit timers of a game's away players and
+// drops its presence, once the game has ended or is cleaned up
+func (ge *GameEngine) forgetPresence(gameID string) {
+	ge.presenceMutex.Lock()
+	defer ge.presenceMutex.Unlock()
+
+	if presence, exists := ge.presence[gameID]; exists {
+		for _, away := range presence.away {
+			if away.timer != nil {
+				away.timer.Stop()
+			}
+		}
+		delete(ge.presence, gameID)
+	}
+}
@@ -0,0 +1,204 @@
+// internal/game/progression.go - Post-match progression pipeline
+package game
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// MatchRewards summarizes what a finished game earned one player
+type MatchRewards struct {
+	PlayerID     string             `json:"player_id"`
+	Result       models.MatchResult `json:"result"`
+	Experience   int                `json:"experience"` // experience gained this match
+	Gold         int                `json:"gold"`       // gold gained this match
+	Cards        map[string]int     `json:"cards"`      // troop ID -> cards gained this match
+	TotalExp     int                `json:"total_experience"`
+	Level        int                `json:"level"`
+	LeveledUp    bool               `json:"leveled_up"`
+	LevelExp     int                `json:"level_experience"` // experience earned within the current level
+	NextLevelExp int                `json:"next_level_experience"`
+	RatingBefore int                `json:"rating_before"`
+	RatingAfter  int                `json:"rating_after"`
+	Place        int                `json:"place,omitempty"` // finishing place, 1 for the winner
+	Stats        models.PlayerStats `json:"stats"`
+}
+
+// ExperienceRewards is the experience a mode awards per result
+type ExperienceRewards struct {
+	Win  int
+	Draw int
+}
+
+// RewardsKept is how many recent games' rewards the pipeline remembers
+const RewardsKept = 1024
+
+// ProgressionPipeline applies experience, currency, stats and rating for
+// finished games to the stored player records, once per game
+type ProgressionPipeline struct {
+	storage   *storage.JSONStorage
+	ratings   *RatingSystem
+	rewards   map[string]map[string]*MatchRewards // game ID -> player ID -> rewards
+	processed []string                            // game IDs in rewards, oldest first
+	mutex     sync.Mutex
+}
+
+func NewProgressionPipeline(storage *storage.JSONStorage, ratings *RatingSystem) *ProgressionPipeline {
+	return &ProgressionPipeline{
+		storage: storage,
+		ratings: ratings,
+		rewards: make(map[string]map[string]*MatchRewards),
+	}
+}
+
+// Process updates every player's record and saves them together. The
+// records are reloaded from storage so in-game state is never persisted.
+// Processing the same game twice returns the first result while the
+// rewards of the game are remembered.
+func (pp *ProgressionPipeline) Process(game *models.Game, experience ExperienceRewards, currency models.MatchCurrency) (map[string]*MatchRewards, error) {
+	if len(game.Players) < 2 {
+		return nil, errors.New("progression needs at least two players")
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if rewards, exists := pp.rewards[game.ID]; exists {
+		return rewards, nil
+	}
+
+	records := make([]*models.Player, len(game.Players))
+	for i, player := range game.Players {
+		record, err := pp.storage.LoadPlayer(player.ID)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+
+	winnerID := ""
+	if game.Winner != nil {
+		winnerID = game.Winner.ID
+	}
+
+	changes, err := pp.rate(game, winnerID, records)
+	if err != nil {
+		return nil, err
+	}
+
+	rewards := make(map[string]*MatchRewards, len(records))
+	for i, record := range records {
+		change := changes[record.ID]
+
+		gained := 0
+		gold, cards := currency.GoldLoss, currency.CardsLoss
+		switch change.Result {
+		case models.ResultWin:
+			gained = experience.Win
+			gold, cards = currency.GoldWin, currency.CardsWin
+		case models.ResultDraw:
+			gained = experience.Draw
+			gold, cards = currency.GoldDraw, currency.CardsDraw
+		}
+
+		oldLevel := record.Level
+		record.AddExperience(gained)
+		record.Stats.Record(change.Result)
+
+		gold += (record.Level - oldLevel) * currency.LevelUpGold
+		record.Gold += gold
+
+		// Cards go to the troops the player brought to this match
+		earned := make(map[string]int)
+		if cards > 0 {
+			for _, troop := range game.Players[i].AvailableTroops {
+				record.AddCards(troop.ID, cards)
+				earned[troop.ID] = cards
+			}
+		}
+
+		_, levelExp, nextLevelExp := models.LevelProgress(record.Experience)
+
+		rewards[record.ID] = &MatchRewards{
+			PlayerID:     record.ID,
+			Result:       change.Result,
+			Experience:   gained,
+			Gold:         gold,
+			Cards:        earned,
+			TotalExp:     record.Experience,
+			Level:        record.Level,
+			LeveledUp:    record.Level > oldLevel,
+			LevelExp:     levelExp,
+			NextLevelExp: nextLevelExp,
+			RatingBefore: change.Before,
+			RatingAfter:  change.After,
+			Place:        game.PlaceOf(record.ID),
+			Stats:        record.Stats,
+		}
+	}
+
+	if err := pp.storage.SavePlayers(records...); err != nil {
+		return nil, err
+	}
+
+	pp.rewards[game.ID] = rewards
+	pp.processed = append(pp.processed, game.ID)
+	if len(pp.processed) > RewardsKept {
+		delete(pp.rewards, pp.processed[0])
+		pp.processed = pp.processed[1:]
+	}
+	return rewards, nil
+}
+
+// rate updates the ratings of the game's players: side against side in
+// team games and by finishing place in free-for-all games
+func (pp *ProgressionPipeline) rate(game *models.Game, winnerID string, records []*models.Player) (map[string]models.RatingChange, error) {
+	if game.TeamSize <= 1 {
+		if len(records) == 2 {
+			return pp.ratings.RateGame(game.ID, game.Mode, winnerID, records[0], records[1], time.Now())
+		}
+		return pp.ratings.RateFreeForAll(game.ID, game.Mode, game.Places, records, time.Now())
+	}
+
+	teams := make([][]*models.Player, game.Teams())
+	for i, record := range records {
+		team := game.TeamOf(game.Players[i].ID)
+		teams[team] = append(teams[team], record)
+	}
+
+	winningTeam := -1
+	if winnerID != "" {
+		winningTeam = game.TeamOf(winnerID)
+	}
+	return pp.ratings.RateTeamGame(game.ID, game.Mode, winningTeam, teams, time.Now())
+}
+
+// Rewards returns the rewards of a processed game
+func (pp *ProgressionPipeline) Rewards(gameID string) (map[string]*MatchRewards, bool) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	rewards, exists := pp.rewards[gameID]
+	return rewards, exists
+}
+
+// Forget drops the rewards of a game that is cleaned up
+func (pp *ProgressionPipeline) Forget(gameID string) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if _, exists := pp.rewards[gameID]; !exists {
+		return
+	}
+	delete(pp.rewards, gameID)
+	for i, id := range pp.processed {
+		if id == gameID {
+			pp.processed = append(pp.processed[:i], pp.processed[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// internal/game/rating.go - Elo rating updates for finished games
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/models"
+)
+
+type RatingSystem struct {
+	kFactor            int
+	provisionalKFactor int
+	provisionalGames   int
+}
+
+func NewRatingSystem(cfg config.RatingConfig) *RatingSystem {
+	rs := &RatingSystem{
+		kFactor:            cfg.KFactor,
+		provisionalKFactor: cfg.ProvisionalKFactor,
+		provisionalGames:   cfg.ProvisionalGames,
+	}
+	if rs.kFactor <= 0 {
+		rs.kFactor = 32
+	}
+	if rs.provisionalKFactor < rs.kFactor {
+		rs.provisionalKFactor = rs.kFactor
+	}
+	return rs
+}
+
+// ExpectedScore is the chance, between 0 and 1, that a player rated rating
+// beats one rated opponentRating
+func ExpectedScore(rating, opponentRating int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+}
+
+// RateGame updates the ratings of a finished two-player game. The players
+// are the stored records to update, in any order; winnerID is empty for a
+// draw. It returns each player's rating change keyed by player ID.
+func (rs *RatingSystem) RateGame(gameID string, mode models.GameMode, winnerID string, first, second *models.Player, now time.Time) (map[string]models.RatingChange, error) {
+	if first.ID == second.ID {
+		return nil, errors.New("a player cannot be rated against themselves")
+	}
+	if winnerID != "" && winnerID != first.ID && winnerID != second.ID {
+		return nil, errors.New("winner did not play in the game")
+	}
+
+	firstRating := first.RatingFor(mode)
+	secondRating := second.RatingFor(mode)
+
+	changes := map[string]models.RatingChange{
+		first.ID:  rs.change(gameID, first.ID, winnerID, firstRating, second.ID, secondRating, now),
+		second.ID: rs.change(gameID, second.ID, winnerID, secondRating, first.ID, firstRating, now),
+	}
+
+	firstRating.Apply(changes[first.ID])
+	secondRating.Apply(changes[second.ID])
+
+	return changes, nil
+}
+
+// RateTeamGame updates the ratings of a finished game between two sides.
+// Each player is rated against the average rating of the other side, whose
+// first player is recorded as the opponent; winningTeam is -1 for a draw.
+func (rs *RatingSystem) RateTeamGame(gameID string, mode models.GameMode, winningTeam int, teams [][]*models.Player, now time.Time) (map[string]models.RatingChange, error) {
+	if len(teams) != 2 || len(teams[0]) == 0 || len(teams[1]) == 0 {
+		return nil, errors.New("team games are rated between two sides")
+	}
+	if winningTeam >= len(teams) {
+		return nil, errors.New("winning team did not play in the game")
+	}
+
+	averages := make([]int, len(teams))
+	for i, team := range teams {
+		total := 0
+		for _, player := range team {
+			total += player.RatingFor(mode).Value
+		}
+		averages[i] = int(math.Round(float64(total) / float64(len(team))))
+	}
+
+	changes := make(map[string]models.RatingChange)
+	for i, team := range teams {
+		opponents := teams[1-i]
+		for _, player := range team {
+			if _, rated := changes[player.ID]; rated {
+				return nil, errors.New("a player cannot be rated twice in one game")
+			}
+
+			result := models.ResultDraw
+			switch winningTeam {
+			case i:
+				result = models.ResultWin
+			case 1 - i:
+				result = models.ResultLoss
+			}
+			changes[player.ID] = rs.rate(gameID, player.RatingFor(mode), result, opponents[0].ID, averages[1-i], now)
+		}
+	}
+
+	for _, team := range teams {
+		for _, player := range team {
+			player.RatingFor(mode).Apply(changes[player.ID])
+		}
+	}
+
+	return changes, nil
+}
+
+// RateFreeForAll updates the ratings of a finished game of three or more
+// players from their finishing places. Every player is scored against each
+// of the others, a better place winning and an equal one drawing, with the
+// K-factor shared between the opponents. The recorded opponent rating is
+// the others' average, and only a sole first place counts as a win.
+func (rs *RatingSystem) RateFreeForAll(gameID string, mode models.GameMode, places map[string]int, players []*models.Player, now time.Time) (map[string]models.RatingChange, error) {
+	if len(players) < 3 {
+		return nil, errors.New("free-for-all games are rated between three or more players")
+	}
+
+	ratings := make([]*models.Rating, len(players))
+	seen := make(map[string]bool, len(players))
+	firsts := 0
+	for i, player := range players {
+		if seen[player.ID] {
+			return nil, errors.New("a player cannot be rated twice in one game")
+		}
+		seen[player.ID] = true
+		if places[player.ID] <= 0 {
+			return nil, fmt.Errorf("player %s has no place", player.ID)
+		}
+		if places[player.ID] == 1 {
+			firsts++
+		}
+		ratings[i] = player.RatingFor(mode)
+	}
+
+	changes := make(map[string]models.RatingChange, len(players))
+	for i, player := range players {
+		rating := ratings[i]
+		place := places[player.ID]
+
+		delta, total := 0.0, 0
+		for j, other := range players {
+			if i == j {
+				continue
+			}
+			score := 0.5
+			switch {
+			case place < places[other.ID]:
+				score = 1
+			case place > places[other.ID]:
+				score = 0
+			}
+			delta += score - ExpectedScore(rating.Value, ratings[j].Value)
+			total += ratings[j].Value
+		}
+		delta *= float64(rs.kFactorFor(rating)) / float64(len(players)-1)
+
+		result := models.ResultLoss
+		if place == 1 {
+			result = models.ResultWin
+			if firsts > 1 {
+				result = models.ResultDraw
+			}
+		}
+
+		changes[player.ID] = models.RatingChange{
+			GameID:         gameID,
+			OpponentRating: int(math.Round(float64(total) / float64(len(players)-1))),
+			Result:         result,
+			Before:         rating.Value,
+			After:          rating.Value + int(math.Round(delta)),
+			Timestamp:      now,
+		}
+	}
+
+	for i, player := range players {
+		ratings[i].Apply(changes[player.ID])
+	}
+
+	return changes, nil
+}
+
+// kFactorFor returns the K-factor of a rating, larger while it is provisional
+func (rs *RatingSystem) kFactorFor(rating *models.Rating) int {
+	if rating.Games < rs.provisionalGames {
+		return rs.provisionalKFactor
+	}
+	return rs.kFactor
+}
+
+func (rs *RatingSystem) change(gameID, playerID, winnerID string, rating *models.Rating, opponentID string, opponentRating *models.Rating, now time.Time) models.RatingChange {
+	result := models.ResultDraw
+	switch winnerID {
+	case playerID:
+		result = models.ResultWin
+	case opponentID:
+		result = models.ResultLoss
+	}
+
+	return rs.rate(gameID, rating, result, opponentID, opponentRating.Value, now)
+}
+
+// rate moves a rating by the result against an opponent rated opponentRating
+func (rs *RatingSystem) rate(gameID string, rating *models.Rating, result models.MatchResult, opponentID string, opponentRating int, now time.Time) models.RatingChange {
+	score := 0.5
+	switch result {
+	case models.ResultWin:
+		score = 1
+	case models.ResultLoss:
+		score = 0
+	}
+
+	delta := float64(rs.kFactorFor(rating)) * (score - ExpectedScore(rating.Value, opponentRating))
+
+	return models.RatingChange{
+		GameID:         gameID,
+		OpponentID:     opponentID,
+		OpponentRating: opponentRating,
+		Result:         result,
+		Before:         rating.Value,
+		After:          rating.Value + int(math.Round(delta)),
+		Timestamp:      now,
+	}
+}
@@ -0,0 +1,219 @@
+// internal/game/replay.go - Match replay re-simulation
+package game
+
+import (
+	"fmt"
+
+	"tcr-game/internal/models"
+)
+
+type ReplayEngine struct {
+	battleEngine *BattleEngine
+	arenaConfig  ArenaConfig
+}
+
+func NewReplayEngine(critMultiplier float64) *ReplayEngine {
+	return &ReplayEngine{
+		battleEngine: NewBattleEngine(critMultiplier),
+		arenaConfig:  DefaultArenaConfig(),
+	}
+}
+
+// Simulate rebuilds the match from its starting snapshot and seed, then
+// re-applies every recorded action through the battle engine. Enhanced
+// matches are re-run tick by tick on a fresh arena.
+func (re *ReplayEngine) Simulate(replay *models.Replay) (*models.Game, error) {
+	if replay.Version != models.ReplayVersion {
+		return nil, fmt.Errorf("unsupported replay version: %d", replay.Version)
+	}
+
+	game := models.NewGameWithSeed(replay.GameID, replay.Mode, replay.Seed)
+	game.RNG().Skip(replay.RNGDraws)
+	game.MaxPlayers = len(replay.Players)
+	game.TeamSize = replay.TeamSize
+	game.State = models.InProgress
+	game.StartTime = replay.StartTime
+
+	for _, snapshot := range replay.Players {
+		player := models.NewPlayer(snapshot.ID, snapshot.Username, "")
+		player.AvailableTroops = make([]*models.Troop, len(snapshot.Troops))
+		for i := range snapshot.Troops {
+			troop := snapshot.Troops[i]
+			player.AvailableTroops[i] = &troop
+		}
+		player.Towers = make([]*models.Tower, len(snapshot.Towers))
+		for i := range snapshot.Towers {
+			tower := snapshot.Towers[i]
+			player.Towers[i] = &tower
+		}
+		game.AddPlayer(player)
+	}
+	game.ShareTeamKings()
+
+	var winnerID string
+	var err error
+	if realTime(replay.Mode) {
+		winnerID, err = re.simulateArena(game, replay)
+	} else {
+		winnerID, err = re.simulateTurns(game, replay)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	game.State = models.Finished
+	game.EndTime = replay.EndTime
+	game.Winner = re.findPlayer(game, winnerID)
+
+	return game, nil
+}
+
+// simulateTurns applies each recorded attack directly to the towers. Turns
+// passed on timeout change nothing, and a forfeit knocks the player out.
+func (re *ReplayEngine) simulateTurns(game *models.Game, replay *models.Replay) (string, error) {
+	winnerID := ""
+	ended := false
+	for _, action := range replay.Actions {
+		if ended {
+			return "", fmt.Errorf("action %d recorded after the game ended", action.Sequence)
+		}
+
+		switch action.Type {
+		case "pass":
+			continue
+		case "forfeit":
+			ended, winnerID = re.battleEngine.Eliminate(game, action.PlayerID)
+			continue
+		}
+
+		result, err := re.battleEngine.ExecuteAttack(game, action.PlayerID, action.TargetPlayer, action.TroopID, action.TargetTower)
+		if err != nil {
+			return "", fmt.Errorf("action %d: %v", action.Sequence, err)
+		}
+
+		if result.GameEnded {
+			ended = true
+			winnerID = result.Winner
+		}
+	}
+
+	if !ended {
+		winnerID = re.battleEngine.GetGameWinner(game)
+	}
+
+	return winnerID, nil
+}
+
+// simulateArena spawns each recorded troop at the tick it was played and
+// steps the arena for as many ticks as the original match ran, applying
+// sudden death and the tiebreaker if the match reached them. A forfeit
+// ends the match at its tick.
+func (re *ReplayEngine) simulateArena(game *models.Game, replay *models.Replay) (string, error) {
+	arena := NewArena(game, re.battleEngine, re.arenaConfig)
+	next := 0
+
+	for {
+		for next < len(replay.Actions) && replay.Actions[next].Tick == arena.Tick() {
+			action := replay.Actions[next]
+			next++
+			if action.Type == "forfeit" {
+				if ended, winner := re.battleEngine.Eliminate(game, action.PlayerID); ended {
+					if arena.Tick() != replay.Ticks || next < len(replay.Actions) {
+						return "", fmt.Errorf("game forfeited at tick %d, recorded %d", arena.Tick(), replay.Ticks)
+					}
+					return winner, nil
+				}
+				continue
+			}
+			if err := re.spawn(game, arena, action); err != nil {
+				return "", err
+			}
+		}
+
+		if arena.Tick() >= replay.Ticks {
+			break
+		}
+
+		tick := arena.Step()
+		winner := tick.Winner
+		if !tick.GameEnded && replay.SuddenDeathTick > 0 && arena.Tick() > replay.SuddenDeathTick {
+			// Sudden death started tied, so the first tower lost decides it
+			winner = re.battleEngine.GetGameWinner(game)
+		}
+		if tick.GameEnded || winner != "" {
+			if arena.Tick() != replay.Ticks || next < len(replay.Actions) {
+				return "", fmt.Errorf("game ended at tick %d, recorded %d", arena.Tick(), replay.Ticks)
+			}
+			return winner, nil
+		}
+	}
+
+	if next < len(replay.Actions) {
+		return "", fmt.Errorf("action %d recorded at tick %d, after the last tick", replay.Actions[next].Sequence, replay.Actions[next].Tick)
+	}
+
+	winner := re.battleEngine.GetGameWinner(game)
+	if winner == "" && replay.Tiebreaker {
+		winner = re.battleEngine.TiebreakWinner(game)
+	}
+	return winner, nil
+}
+
+func (re *ReplayEngine) spawn(game *models.Game, arena *Arena, action models.ReplayAction) error {
+	owner := re.findPlayer(game, action.PlayerID)
+	if owner == nil {
+		return fmt.Errorf("action %d: unknown player %s", action.Sequence, action.PlayerID)
+	}
+
+	for _, troop := range owner.AvailableTroops {
+		if troop.ID == action.TroopID {
+			_, err := arena.Spawn(owner, troop, action.Lane)
+			if err != nil {
+				return fmt.Errorf("action %d: %v", action.Sequence, err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("action %d: troop not found: %s", action.Sequence, action.TroopID)
+}
+
+// Verify re-simulates the replay and checks it ends in the recorded state
+func (re *ReplayEngine) Verify(replay *models.Replay) error {
+	if replay.Result == nil {
+		return fmt.Errorf("replay %s has no recorded result", replay.GameID)
+	}
+
+	game, err := re.Simulate(replay)
+	if err != nil {
+		return err
+	}
+
+	result := models.NewReplayResult(game)
+	if result.WinnerID != replay.Result.WinnerID {
+		return fmt.Errorf("winner mismatch: recorded %q, simulated %q", replay.Result.WinnerID, result.WinnerID)
+	}
+
+	for playerID, expected := range replay.Result.TowerHP {
+		actual, exists := result.TowerHP[playerID]
+		if !exists || len(actual) != len(expected) {
+			return fmt.Errorf("tower mismatch for player %s", playerID)
+		}
+		for i := range expected {
+			if actual[i] != expected[i] {
+				return fmt.Errorf("tower %d of player %s: recorded %d HP, simulated %d HP", i, playerID, expected[i], actual[i])
+			}
+		}
+	}
+
+	return nil
+}
+
+func (re *ReplayEngine) findPlayer(game *models.Game, playerID string) *models.Player {
+	for _, player := range game.Players {
+		if player.ID == playerID {
+			return player
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// internal/game/rules.go - Pluggable rule sets, one per game mode
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"tcr-game/internal/models"
+)
+
+// RuleSet is the rules of one game mode. The engine and the HTTP handlers
+// only talk to games through it, so a mode is added by registering a
+// RuleSetDefinition rather than by editing them.
+type RuleSet interface {
+	// StartGame starts a game once it has its players. Real-time rule sets
+	// run their own clock from here.
+	StartGame(game *models.Game) error
+	// ValidateAction decodes an action sent by a client into the mode's
+	// action type and checks it is well formed. Whether it can be played
+	// right now is decided by ApplyAction.
+	ValidateAction(game *models.Game, playerID string, payload json.RawMessage) (interface{}, error)
+	// ApplyAction plays an action. Actions breaking the rules are reported
+	// in the result; errors are reserved for unknown games and action types.
+	// A rule set ends the game itself once an action or its clock decides it.
+	ApplyAction(game *models.Game, playerID string, action interface{}) (ActionResult, error)
+	// State projects a game for clients
+	State(game *models.Game) (map[string]interface{}, error)
+	// End ends a game early
+	End(game *models.Game, reason string) error
+	// CleanupGame releases the game's timers
+	CleanupGame(gameID string)
+}
+
+// Forfeiter is implemented by rule sets that can knock out a player who
+// left a game. Games of other modes are ended with End instead.
+type Forfeiter interface {
+	Forfeit(game *models.Game, playerID string, reason string) error
+}
+
+// Pauser is implemented by rule sets whose clock stops while a player is
+// away. Resume restarts it with the time the turn had left.
+type Pauser interface {
+	Pause(game *models.Game)
+	Resume(game *models.Game)
+}
+
+// ActionResult is the mode-specific result of an applied action
+type ActionResult interface {
+	Accepted() bool
+	// Battle is the tower attack the action made, if any
+	Battle() *BattleResult
+}
+
+// AttackRules are the parts of a troop attacking a tower that differ between modes
+type AttackRules struct {
+	OrderedTargets bool // towers must fall in layout order
+	CriticalHits   bool // troops and towers roll for critical hits
+	SpendMana      bool // the attacker pays the troop's mana cost
+	Retaliation    bool // the attacked tower fires back if it survives
+	ContinueOnKill bool // destroying a tower lets the player attack again
+}
+
+// RuleSetDefinition registers a game mode
+type RuleSetDefinition struct {
+	Mode   models.GameMode
+	Attack AttackRules
+	// Players is how many players a game seats, 2 when zero. Modes with a
+	// MinPlayers may also be created for between MinPlayers and Players
+	// seats. TeamSize splits the players into sides of that many players;
+	// zero plays everyone alone.
+	Players    int
+	MinPlayers int
+	TeamSize   int
+	// RealTime modes have their replays re-simulated in the arena tick by
+	// tick rather than action by action
+	RealTime bool
+	// Experience is the experience the mode awards under a balance version
+	Experience func(balance *Balance) ExperienceRewards
+	// New builds the mode's rule set for a balance version. The rule set
+	// publishes its game endings and other events to events.
+	New func(balance *Balance, events *EventManager) RuleSet
+}
+
+var (
+	ruleSets      = make(map[models.GameMode]RuleSetDefinition)
+	ruleSetsMutex sync.RWMutex
+)
+
+// RegisterRuleSet makes a mode available to engines created afterwards.
+// Built-in modes register themselves from init.
+func RegisterRuleSet(definition RuleSetDefinition) {
+	if definition.Mode == "" || definition.New == nil {
+		panic("rule set needs a mode and a constructor")
+	}
+
+	ruleSetsMutex.Lock()
+	defer ruleSetsMutex.Unlock()
+
+	if _, exists := ruleSets[definition.Mode]; exists {
+		panic(fmt.Sprintf("rule set for mode %q registered twice", definition.Mode))
+	}
+	ruleSets[definition.Mode] = definition
+}
+
+// IsRegisteredMode reports whether games of a mode can be created
+func IsRegisteredMode(mode models.GameMode) bool {
+	_, exists := ruleSetDefinition(mode)
+	return exists
+}
+
+func ruleSetDefinition(mode models.GameMode) (RuleSetDefinition, bool) {
+	ruleSetsMutex.RLock()
+	defer ruleSetsMutex.RUnlock()
+
+	definition, exists := ruleSets[mode]
+	return definition, exists
+}
+
+// newGame creates a game of a mode seated for players, or for the mode's
+// players when players is zero
+func newGame(gameID string, mode models.GameMode, players int) (*models.Game, error) {
+	definition, exists := ruleSetDefinition(mode)
+	if !exists {
+		return nil, errors.New("invalid game mode")
+	}
+
+	seats := playersFor(mode)
+	if players != 0 && players != seats {
+		if definition.MinPlayers <= 0 || players < definition.MinPlayers || players > seats {
+			return nil, fmt.Errorf("%s games cannot seat %d players", mode, players)
+		}
+		seats = players
+	}
+
+	game := models.NewGame(gameID, mode)
+	game.MaxPlayers = seats
+	game.TeamSize = definition.TeamSize
+	return game, nil
+}
+
+// playersFor returns how many players a game of a mode seats
+func playersFor(mode models.GameMode) int {
+	definition, _ := ruleSetDefinition(mode)
+	if definition.Players <= 0 {
+		return 2
+	}
+	return definition.Players
+}
+
+// attackRules returns a mode's attack rules; unknown modes get none of them
+func attackRules(mode models.GameMode) AttackRules {
+	definition, _ := ruleSetDefinition(mode)
+	return definition.Attack
+}
+
+// realTime reports whether a mode's replays are simulated in the arena
+func realTime(mode models.GameMode) bool {
+	definition, _ := ruleSetDefinition(mode)
+	return definition.RealTime
+}
+
+// experienceFor returns the experience a mode awards under a balance version
+func experienceFor(mode models.GameMode, balance *Balance) ExperienceRewards {
+	definition, exists := ruleSetDefinition(mode)
+	if !exists || definition.Experience == nil {
+		return ExperienceRewards{}
+	}
+	return definition.Experience(balance)
+}
+
+// newRuleSets builds the rule set of every registered mode for a balance version
+func newRuleSets(balance *Balance, events *EventManager) map[models.GameMode]RuleSet {
+	ruleSetsMutex.RLock()
+	defer ruleSetsMutex.RUnlock()
+
+	built := make(map[models.GameMode]RuleSet, len(ruleSets))
+	for mode, definition := range ruleSets {
+		built[mode] = definition.New(balance, events)
+	}
+	return built
+}
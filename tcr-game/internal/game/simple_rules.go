@@ -2,9 +2,10 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
+	"sync"
 	"time"
 	
 	"tcr-game/internal/models"
@@ -14,12 +15,17 @@ type SimpleGameManager struct {
 	battleEngine *BattleEngine
 	maxPlayers   int
 	turnTime     int // seconds
+	maxTimeouts  int // consecutive timeouts before a forfeit, 0 never forfeits
+	eventManager *EventManager
+	clocks       map[string]*turnClock
+	clocksMutex  sync.Mutex // guards clocks and each clock's deadline
 }
 
 // Define TurnAction in game package
 type TurnAction struct {
 	Type       string `json:"type"`        // "attack"
 	TroopID    string `json:"troop_id"`    
+	TargetPlayer string `json:"target_player,omitempty"` // needed when there is more than one opponent
 	TargetTower int   `json:"target_tower"`
 }
 
@@ -29,30 +35,45 @@ type TurnResult struct {
 	CanContinue    bool         `json:"can_continue"`
 	NextPlayer     string       `json:"next_player"`
 	TurnRemaining  int          `json:"turn_remaining_seconds"`
+	GameEnded      bool         `json:"game_ended"`
+	Winner         string       `json:"winner,omitempty"`
 	Error          string       `json:"error,omitempty"`
 }
 
-func NewSimpleGameManager(maxPlayers, turnTime int, critMultiplier float64) *SimpleGameManager {
+func NewSimpleGameManager(maxPlayers, turnTime, maxTimeouts int, critMultiplier float64) *SimpleGameManager {
 	return &SimpleGameManager{
 		battleEngine: NewBattleEngine(critMultiplier),
 		maxPlayers:   maxPlayers,
 		turnTime:     turnTime,
+		maxTimeouts:  maxTimeouts,
+		clocks:       make(map[string]*turnClock),
 	}
 }
 
+// SetEventManager registers the event manager game endings are published to
+func (sgm *SimpleGameManager) SetEventManager(eventManager *EventManager) {
+	sgm.eventManager = eventManager
+}
+
 // StartGame initializes a simple mode game
 func (sgm *SimpleGameManager) StartGame(game *models.Game) error {
-	if len(game.Players) != sgm.maxPlayers {
-		return fmt.Errorf("need exactly %d players, got %d", sgm.maxPlayers, len(game.Players))
+	if !game.IsFull() || len(game.Players) > sgm.maxPlayers {
+		return fmt.Errorf("need %d to %d players, got %d", game.Capacity(), sgm.maxPlayers, len(game.Players))
 	}
 	
 	// Assign random troops to each player
 	for _, player := range game.Players {
-		if err := sgm.assignRandomTroops(player); err != nil {
+		if err := sgm.assignRandomTroops(game, player); err != nil {
 			return fmt.Errorf("failed to assign troops to player %s: %v", player.ID, err)
 		}
 	}
 	
+	sgm.beginMatch(game)
+	return nil
+}
+
+// beginMatch starts the first turn once every player has their troops
+func (sgm *SimpleGameManager) beginMatch(game *models.Game) {
 	// Initialize game state
 	game.Start()
 	game.CurrentTurn = 0 // First player's turn
@@ -63,11 +84,13 @@ func (sgm *SimpleGameManager) StartGame(game *models.Game) error {
 		player.MaxMana = 10
 	}
 	
-	return nil
+	game.BeginReplay()
+	sgm.startClock(game)
 }
 
-// assignRandomTroops gives each player 3 random troops from the available list
-func (sgm *SimpleGameManager) assignRandomTroops(player *models.Player) error {
+// assignRandomTroops gives each player 3 random troops from the available list,
+// drawn from the game's random source
+func (sgm *SimpleGameManager) assignRandomTroops(game *models.Game, player *models.Player) error {
 	// Load all available troops
 	allTroops := player.AvailableTroops
 	if len(allTroops) == 0 {
@@ -79,7 +102,7 @@ func (sgm *SimpleGameManager) assignRandomTroops(player *models.Player) error {
 	usedIndices := make(map[int]bool)
 	
 	for len(selected) < 3 && len(selected) < len(allTroops) {
-		index := rand.Intn(len(allTroops))
+		index := game.RNG().Intn(len(allTroops))
 		if !usedIndices[index] {
 			usedIndices[index] = true
 			// Create a copy of the troop for this game
@@ -94,6 +117,20 @@ func (sgm *SimpleGameManager) assignRandomTroops(player *models.Player) error {
 
 // ProcessTurn handles a player's turn in simple mode
 func (sgm *SimpleGameManager) ProcessTurn(game *models.Game, playerID string, action TurnAction) (*TurnResult, error) {
+	// Turns and timeouts of the same game are handled one at a time
+	clock := sgm.clock(game.ID)
+	if clock != nil {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+	}
+	
+	if game.State != models.InProgress {
+		return &TurnResult{
+			Success: false,
+			Error:   "game is not in progress",
+		}, nil
+	}
+	
 	// Validate it's the player's turn
 	currentPlayer := game.Players[game.CurrentTurn]
 	if currentPlayer.ID != playerID {
@@ -111,8 +148,16 @@ func (sgm *SimpleGameManager) ProcessTurn(game *models.Game, playerID string, ac
 		}, nil
 	}
 	
+	// Troops killed by tower fire can no longer attack
+	if _, err := sgm.ValidateTroopSelection(currentPlayer, action.TroopID); err != nil {
+		return &TurnResult{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	
 	// Execute the attack
-	battleResult, err := sgm.battleEngine.ExecuteAttack(game, playerID, action.TroopID, action.TargetTower)
+	battleResult, err := sgm.battleEngine.ExecuteAttack(game, playerID, action.TargetPlayer, action.TroopID, action.TargetTower)
 	if err != nil {
 		return &TurnResult{
 			Success: false,
@@ -120,18 +165,31 @@ func (sgm *SimpleGameManager) ProcessTurn(game *models.Game, playerID string, ac
 		}, nil
 	}
 	
+	game.RecordAction(models.ReplayAction{
+		Kind:        models.ReplaySimpleAction,
+		PlayerID:    playerID,
+		Type:        action.Type,
+		TroopID:     action.TroopID,
+		TargetPlayer: battleResult.DefenderID,
+		TargetTower: action.TargetTower,
+	})
+	sgm.publishEliminated(game, battleResult.Eliminated, "king_tower_destroyed")
+	
 	// Check if the game ended
 	if battleResult.GameEnded {
 		game.State = models.Finished
 		endTime := time.Now()
 		game.EndTime = &endTime
 		game.Winner = sgm.findPlayerByID(game, battleResult.Winner)
+		sgm.finishGame(game, "king_tower_destroyed")
 		
 		return &TurnResult{
 			Success:      true,
 			BattleResult: battleResult,
 			CanContinue:  false,
 			NextPlayer:   "",
+			GameEnded:    true,
+			Winner:       battleResult.Winner,
 		}, nil
 	}
 	
@@ -142,11 +200,40 @@ func (sgm *SimpleGameManager) ProcessTurn(game *models.Game, playerID string, ac
 	if !canContinue {
 		// Switch to next player
 		nextPlayerTurn = (game.CurrentTurn + 1) % len(game.Players)
-		game.CurrentTurn = nextPlayerTurn
 	}
 	
+	// Knocked out players and those whose troops have all been destroyed
+	// are skipped; once nobody can attack, the game is decided on towers lost
+	if !sgm.canAttack(game, game.Players[nextPlayerTurn]) {
+		nextPlayerTurn = sgm.nextAttacker(game, nextPlayerTurn+1)
+		if nextPlayerTurn < 0 {
+			sgm.endGame(game, "troops_exhausted")
+			
+			winner := ""
+			if game.Winner != nil {
+				winner = game.Winner.ID
+			}
+			return &TurnResult{
+				Success:      true,
+				BattleResult: battleResult,
+				CanContinue:  false,
+				NextPlayer:   "",
+				GameEnded:    true,
+				Winner:       winner,
+			}, nil
+		}
+		canContinue = nextPlayerTurn == game.CurrentTurn
+	}
+	
+	game.CurrentTurn = nextPlayerTurn
 	nextPlayer := game.Players[nextPlayerTurn]
 	
+	// A turn played resets the player's timeouts and starts the next clock
+	if clock != nil {
+		clock.timeouts[playerID] = 0
+		sgm.nextTurn(clock, "action")
+	}
+	
 	return &TurnResult{
 		Success:       true,
 		BattleResult:  battleResult,
@@ -156,23 +243,67 @@ func (sgm *SimpleGameManager) ProcessTurn(game *models.Game, playerID string, ac
 	}, nil
 }
 
-// ValidateTroopSelection checks if a troop can be used by the player
+// nextAttacker returns the first player, in turn order from index from,
+// who can still attack, or -1 once nobody can
+func (sgm *SimpleGameManager) nextAttacker(game *models.Game, from int) int {
+	for i := 0; i < len(game.Players); i++ {
+		index := (from + i) % len(game.Players)
+		if sgm.canAttack(game, game.Players[index]) {
+			return index
+		}
+	}
+	return -1
+}
+
+// canAttack reports whether a player is still in the game and has a troop left
+func (sgm *SimpleGameManager) canAttack(game *models.Game, player *models.Player) bool {
+	return game.TeamStanding(game.TeamOf(player.ID)) && sgm.hasLivingTroops(player)
+}
+
+func (sgm *SimpleGameManager) hasLivingTroops(player *models.Player) bool {
+	for _, troop := range player.AvailableTroops {
+		if troop.IsAlive() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTroopSelection checks if a troop can be used by the player. Troops
+// killed by tower fire stay in the player's list but can no longer attack.
 func (sgm *SimpleGameManager) ValidateTroopSelection(player *models.Player, troopID string) (*models.Troop, error) {
 	for _, troop := range player.AvailableTroops {
-		if troop.ID == troopID && troop.IsAlive() {
+		if troop.ID == troopID {
+			if !troop.IsAlive() {
+				return nil, fmt.Errorf("troop already used: %s was destroyed", troopID)
+			}
 			return troop, nil
 		}
 	}
-	return nil, errors.New("troop not available or already used")
+	return nil, errors.New("troop not available")
 }
 
 // GetGameState returns the current state of the game for simple mode
 func (sgm *SimpleGameManager) GetGameState(game *models.Game) map[string]interface{} {
+	clock := sgm.clock(game.ID)
+	if clock != nil {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+	}
+	
 	state := make(map[string]interface{})
 	
 	state["mode"] = game.Mode
 	state["state"] = game.State
 	state["current_turn"] = game.CurrentTurn
+	state["seed"] = game.Seed
+	state["balance_version"] = game.BalanceVersion
+	if remaining, ok := sgm.TurnRemaining(game.ID); ok {
+		state["turn_remaining"] = remaining
+	}
+	if clock != nil {
+		state["turn_paused"] = clock.paused
+	}
 	
 	// Player information
 	players := make([]map[string]interface{}, len(game.Players))
@@ -180,21 +311,41 @@ func (sgm *SimpleGameManager) GetGameState(game *models.Game) map[string]interfa
 		playerData := map[string]interface{}{
 			"id":       player.ID,
 			"username": player.Username,
+			"team":     game.TeamOf(player.ID),
 			"towers":   sgm.getTowerStates(player.Towers),
 			"troops":   sgm.getTroopStates(player.AvailableTroops),
 		}
+		if place := game.PlaceOf(player.ID); place > 0 {
+			playerData["place"] = place
+		}
 		players[i] = playerData
 	}
 	state["players"] = players
+	if game.TeamSize > 1 {
+		state["teams"] = sgm.getTeamStates(game)
+	}
 	
 	// Current player information
 	if game.State == models.InProgress && game.CurrentTurn < len(game.Players) {
 		currentPlayer := game.Players[game.CurrentTurn]
-		state["current_player"] = map[string]interface{}{
-			"id":            currentPlayer.ID,
-			"username":      currentPlayer.Username,
-			"valid_targets": sgm.battleEngine.GetValidTargets(game, sgm.getOpponentID(game, currentPlayer.ID)),
+		current := map[string]interface{}{
+			"id":       currentPlayer.ID,
+			"username": currentPlayer.Username,
+			"team":     game.TeamOf(currentPlayer.ID),
+		}
+		
+		// Towers open to attack, per opponent when there is more than one
+		opponents := standingOpponents(game, currentPlayer.ID)
+		if len(opponents) == 1 {
+			current["valid_targets"] = sgm.battleEngine.GetValidTargets(game, opponents[0].ID)
+		} else {
+			targets := make(map[string][]int, len(opponents))
+			for _, opponent := range opponents {
+				targets[opponent.ID] = sgm.battleEngine.GetValidTargets(game, opponent.ID)
+			}
+			current["valid_targets"] = targets
 		}
+		state["current_player"] = current
 	}
 	
 	// Game result if finished
@@ -202,6 +353,9 @@ func (sgm *SimpleGameManager) GetGameState(game *models.Game) map[string]interfa
 		state["winner"] = ""
 		if game.Winner != nil {
 			state["winner"] = game.Winner.ID
+			if game.TeamSize > 1 {
+				state["winner_team"] = game.TeamOf(game.Winner.ID)
+			}
 		}
 	}
 	
@@ -251,17 +405,19 @@ func (sgm *SimpleGameManager) findPlayerByID(game *models.Game, playerID string)
 	return nil
 }
 
-func (sgm *SimpleGameManager) getOpponentID(game *models.Game, playerID string) string {
-	for _, player := range game.Players {
-		if player.ID != playerID {
-			return player.ID
-		}
-	}
-	return ""
-}
 
 // EndGame handles the end of a simple mode game
 func (sgm *SimpleGameManager) EndGame(game *models.Game, reason string) error {
+	if clock := sgm.clock(game.ID); clock != nil {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+	}
+	
+	return sgm.endGame(game, reason)
+}
+
+// endGame ends the game; callers hold the game's clock lock
+func (sgm *SimpleGameManager) endGame(game *models.Game, reason string) error {
 	if game.State == models.Finished {
 		return errors.New("game already finished")
 	}
@@ -287,9 +443,32 @@ func (sgm *SimpleGameManager) EndGame(game *models.Game, reason string) error {
 		game.Events[len(game.Events)-1].Data.(map[string]interface{})["winner"] = game.Winner.ID
 	}
 	
+	sgm.finishGame(game, reason)
+	
 	return nil
 }
 
+// finishGame stops the turn clock, ranks the players, closes the replay log
+// and publishes the game ending
+func (sgm *SimpleGameManager) finishGame(game *models.Game, reason string) {
+	sgm.stopClock(game.ID)
+	sgm.battleEngine.RecordPlaces(game)
+	game.FinishReplay()
+	if sgm.eventManager != nil {
+		sgm.eventManager.PublishGameEnded(game, reason)
+	}
+}
+
+// publishEliminated announces the players knocked out of a game and their places
+func (sgm *SimpleGameManager) publishEliminated(game *models.Game, playerIDs []string, reason string) {
+	if sgm.eventManager == nil {
+		return
+	}
+	for _, playerID := range playerIDs {
+		sgm.eventManager.PublishPlayerEliminated(game.ID, playerID, game.TeamOf(playerID), game.PlaceOf(playerID), reason)
+	}
+}
+
 // GetAvailableActions returns what actions the current player can take
 func (sgm *SimpleGameManager) GetAvailableActions(game *models.Game, playerID string) ([]string, error) {
 	if game.State != models.InProgress {
@@ -311,4 +490,69 @@ func (sgm *SimpleGameManager) GetAvailableActions(game *models.Game, playerID st
 	}
 	
 	return actions, nil
-}
\ No newline at end of file
+}
+func init() {
+	RegisterRuleSet(RuleSetDefinition{
+		Mode:   models.SimpleMode,
+		Attack: AttackRules{OrderedTargets: true, Retaliation: true, ContinueOnKill: true},
+		Experience: func(balance *Balance) ExperienceRewards {
+			return ExperienceRewards{Win: balance.Game.Simple.ExpWin, Draw: balance.Game.Simple.ExpDraw}
+		},
+		New: func(balance *Balance, events *EventManager) RuleSet {
+			manager := NewSimpleGameManager(
+				balance.Game.Simple.MaxPlayers,
+				balance.Game.Simple.TurnTime,
+				balance.Game.Simple.MaxTimeouts,
+				balance.Game.Enhanced.CritMultiplier,
+			)
+			manager.SetEventManager(events)
+			return manager
+		},
+	})
+}
+
+// ValidateAction decodes a TurnAction
+func (sgm *SimpleGameManager) ValidateAction(game *models.Game, playerID string, payload json.RawMessage) (interface{}, error) {
+	var action TurnAction
+	if err := json.Unmarshal(payload, &action); err != nil {
+		return nil, errors.New("invalid action")
+	}
+	if action.Type != "attack" {
+		return nil, errors.New("invalid action type")
+	}
+	if action.TroopID == "" {
+		return nil, errors.New("troop_id is required")
+	}
+	return action, nil
+}
+
+// ApplyAction plays a TurnAction
+func (sgm *SimpleGameManager) ApplyAction(game *models.Game, playerID string, action interface{}) (ActionResult, error) {
+	turn, ok := action.(TurnAction)
+	if !ok {
+		return nil, fmt.Errorf("simple mode cannot play %T", action)
+	}
+	
+	result, err := sgm.ProcessTurn(game, playerID, turn)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (sgm *SimpleGameManager) State(game *models.Game) (map[string]interface{}, error) {
+	return sgm.GetGameState(game), nil
+}
+
+func (sgm *SimpleGameManager) End(game *models.Game, reason string) error {
+	return sgm.EndGame(game, reason)
+}
+
+// Accepted reports whether the turn was played
+func (tr *TurnResult) Accepted() bool {
+	return tr.Success
+}
+
+func (tr *TurnResult) Battle() *BattleResult {
+	return tr.BattleResult
+}
@@ -0,0 +1,56 @@
+// internal/game/team.go - 2v2 team matches
+package game
+
+import "tcr-game/internal/models"
+
+// Team matches seat four players on two sides. Seats alternate between the
+// sides, so turns go to each side in turn. Each player owns the guard
+// tower on their lane and the side shares its first player's king tower;
+// the side whose king falls loses. Otherwise the match is played in turns
+// under the simple mode rules, and actions name the opponent they target.
+func init() {
+	RegisterRuleSet(RuleSetDefinition{
+		Mode:     models.TeamMode,
+		Attack:   AttackRules{OrderedTargets: true, Retaliation: true, ContinueOnKill: true},
+		Players:  4,
+		TeamSize: 2,
+		Experience: func(balance *Balance) ExperienceRewards {
+			return ExperienceRewards{Win: balance.Game.Team.ExpWin, Draw: balance.Game.Team.ExpDraw}
+		},
+		New: func(balance *Balance, events *EventManager) RuleSet {
+			turnTime := balance.Game.Team.TurnTime
+			if turnTime <= 0 {
+				turnTime = balance.Game.Simple.TurnTime
+			}
+
+			manager := NewSimpleGameManager(
+				4,
+				turnTime,
+				balance.Game.Simple.MaxTimeouts,
+				balance.Game.Enhanced.CritMultiplier,
+			)
+			manager.SetEventManager(events)
+			return manager
+		},
+	})
+}
+
+// getTeamStates summarizes each side of a team game
+func (sgm *SimpleGameManager) getTeamStates(game *models.Game) []map[string]interface{} {
+	states := make([]map[string]interface{}, game.Teams())
+	for team := range states {
+		members := game.TeamMembers(team)
+		players := make([]string, len(members))
+		for i, member := range members {
+			players[i] = member.ID
+		}
+
+		states[team] = map[string]interface{}{
+			"team":        team,
+			"players":     players,
+			"towers_lost": sgm.battleEngine.CountTeamDestroyedTowers(game, team),
+			"standing":    game.TeamStanding(team),
+		}
+	}
+	return states
+}
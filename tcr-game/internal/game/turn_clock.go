@@ -0,0 +1,265 @@
+// internal/game/turn_clock.go - Server-side turn timer for simple mode
+package game
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"tcr-game/internal/models"
+)
+
+// turnClock times the current turn of one simple game. Its mutex also
+// serializes turns with timeouts, so a turn played as the clock runs out
+// is either accepted or passed, never both. A paused clock keeps the time
+// the turn had left instead of a deadline.
+type turnClock struct {
+	game      *models.Game
+	timer     *time.Timer
+	deadline  time.Time
+	paused    bool
+	remaining time.Duration
+	turn      int            // counts turns so a timer from an earlier turn is ignored
+	timeouts  map[string]int // consecutive timed out turns per player
+	mutex     sync.Mutex
+}
+
+// startClock starts timing the first turn. Games without a turn time are untimed.
+func (sgm *SimpleGameManager) startClock(game *models.Game) {
+	if sgm.turnTime <= 0 {
+		return
+	}
+
+	clock := &turnClock{
+		game:     game,
+		timeouts: make(map[string]int),
+	}
+
+	sgm.clocksMutex.Lock()
+	sgm.clocks[game.ID] = clock
+	sgm.clocksMutex.Unlock()
+
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	sgm.nextTurn(clock, "start")
+}
+
+func (sgm *SimpleGameManager) clock(gameID string) *turnClock {
+	sgm.clocksMutex.Lock()
+	defer sgm.clocksMutex.Unlock()
+
+	return sgm.clocks[gameID]
+}
+
+// nextTurn restarts the clock for the current player and announces the
+// turn. Callers hold the clock lock.
+func (sgm *SimpleGameManager) nextTurn(clock *turnClock, reason string) {
+	clock.turn++
+
+	sgm.clocksMutex.Lock()
+	if sgm.clocks[clock.game.ID] != clock {
+		// The clock was stopped
+		sgm.clocksMutex.Unlock()
+		return
+	}
+	sgm.runFor(clock, time.Duration(sgm.turnTime)*time.Second)
+	sgm.clocksMutex.Unlock()
+
+	if sgm.eventManager != nil {
+		current := clock.game.Players[clock.game.CurrentTurn]
+		sgm.eventManager.PublishTurnChanged(clock.game.ID, current.ID, clock.game.TeamOf(current.ID), sgm.turnTime, clock.timeouts[current.ID], reason)
+	}
+}
+
+// runFor has the current turn time out after duration, or holds it at
+// duration while the clock is paused. Callers hold both clock locks.
+func (sgm *SimpleGameManager) runFor(clock *turnClock, duration time.Duration) {
+	if clock.timer != nil {
+		clock.timer.Stop()
+	}
+	if clock.paused {
+		clock.remaining = duration
+		return
+	}
+
+	turn := clock.turn
+	clock.deadline = time.Now().Add(duration)
+	clock.timer = time.AfterFunc(duration, func() {
+		sgm.turnTimedOut(clock, turn)
+	})
+}
+
+// turnTimedOut passes the turn of a player who let the clock run out, or
+// ends the game as a forfeit after too many timeouts in a row
+func (sgm *SimpleGameManager) turnTimedOut(clock *turnClock, turn int) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	game := clock.game
+	if sgm.clock(game.ID) != clock || clock.turn != turn || clock.paused || game.State != models.InProgress {
+		return
+	}
+
+	player := game.Players[game.CurrentTurn]
+	clock.timeouts[player.ID]++
+
+	if sgm.maxTimeouts > 0 && clock.timeouts[player.ID] >= sgm.maxTimeouts {
+		sgm.forfeit(clock, game, player.ID, "forfeit")
+		return
+	}
+
+	game.RecordAction(models.ReplayAction{
+		Kind:     models.ReplaySimpleAction,
+		PlayerID: player.ID,
+		Type:     "pass",
+	})
+
+	// The turn passes to the next player who still has troops
+	if next := sgm.nextAttacker(game, game.CurrentTurn+1); next >= 0 {
+		game.CurrentTurn = next
+	}
+	sgm.nextTurn(clock, "timeout")
+}
+
+// Forfeit knocks out a player who left the game. The others play on, or
+// the last side standing wins.
+func (sgm *SimpleGameManager) Forfeit(game *models.Game, playerID string, reason string) error {
+	clock := sgm.clock(game.ID)
+	if clock != nil {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+	}
+
+	if game.State != models.InProgress {
+		return errors.New("game is not in progress")
+	}
+	if game.PlaceOf(playerID) > 0 {
+		return errors.New("player is already out")
+	}
+	sgm.forfeit(clock, game, playerID, reason)
+	return nil
+}
+
+// forfeit knocks a player out and passes their turn on. Callers hold the
+// clock lock of timed games.
+func (sgm *SimpleGameManager) forfeit(clock *turnClock, game *models.Game, playerID string, reason string) {
+	game.RecordAction(models.ReplayAction{
+		Kind:     models.ReplaySimpleAction,
+		PlayerID: playerID,
+		Type:     "forfeit",
+	})
+	ended, winnerID := sgm.battleEngine.Eliminate(game, playerID)
+	sgm.publishEliminated(game, []string{playerID}, reason)
+	if ended {
+		game.Winner = sgm.findPlayerByID(game, winnerID)
+		sgm.endGame(game, reason)
+		return
+	}
+	if game.Players[game.CurrentTurn].ID != playerID {
+		return
+	}
+
+	// The others play on without the player who forfeited
+	next := sgm.nextAttacker(game, game.CurrentTurn+1)
+	if next < 0 {
+		sgm.endGame(game, "troops_exhausted")
+		return
+	}
+	game.CurrentTurn = next
+	if clock != nil {
+		sgm.nextTurn(clock, "forfeit")
+	}
+}
+
+// Pause stops the game's turn clock, keeping the time the turn has left
+func (sgm *SimpleGameManager) Pause(game *models.Game) {
+	clock := sgm.clock(game.ID)
+	if clock == nil {
+		return
+	}
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	sgm.clocksMutex.Lock()
+	defer sgm.clocksMutex.Unlock()
+	if clock.paused || sgm.clocks[game.ID] != clock {
+		return
+	}
+	if clock.timer != nil {
+		clock.timer.Stop()
+	}
+	clock.paused = true
+	clock.remaining = time.Until(clock.deadline)
+}
+
+// Resume restarts a paused turn clock with the time the turn had left and
+// announces the turn again
+func (sgm *SimpleGameManager) Resume(game *models.Game) {
+	clock := sgm.clock(game.ID)
+	if clock == nil {
+		return
+	}
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	sgm.clocksMutex.Lock()
+	if !clock.paused || sgm.clocks[game.ID] != clock {
+		sgm.clocksMutex.Unlock()
+		return
+	}
+	clock.paused = false
+	sgm.runFor(clock, clock.remaining)
+	remaining := wholeSeconds(clock.remaining)
+	sgm.clocksMutex.Unlock()
+
+	if sgm.eventManager != nil && game.State == models.InProgress {
+		current := game.Players[game.CurrentTurn]
+		sgm.eventManager.PublishTurnChanged(game.ID, current.ID, game.TeamOf(current.ID), remaining, clock.timeouts[current.ID], "resume")
+	}
+}
+
+// stopClock stops and forgets a game's clock. It does not take the clock
+// lock, so it can be called while a turn is being processed.
+func (sgm *SimpleGameManager) stopClock(gameID string) {
+	sgm.clocksMutex.Lock()
+	defer sgm.clocksMutex.Unlock()
+
+	clock, exists := sgm.clocks[gameID]
+	if !exists {
+		return
+	}
+	if clock.timer != nil {
+		clock.timer.Stop()
+	}
+	delete(sgm.clocks, gameID)
+}
+
+// TurnRemaining returns the whole seconds left in the current turn of a timed game
+func (sgm *SimpleGameManager) TurnRemaining(gameID string) (int, bool) {
+	sgm.clocksMutex.Lock()
+	defer sgm.clocksMutex.Unlock()
+
+	clock, exists := sgm.clocks[gameID]
+	if !exists {
+		return 0, false
+	}
+	if clock.paused {
+		return wholeSeconds(clock.remaining), true
+	}
+	return wholeSeconds(time.Until(clock.deadline)), true
+}
+
+// wholeSeconds rounds a time left up to whole seconds
+func wholeSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+// CleanupGame stops the game's turn clock
+func (sgm *SimpleGameManager) CleanupGame(gameID string) {
+	sgm.stopClock(gameID)
+}
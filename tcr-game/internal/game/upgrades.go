@@ -0,0 +1,187 @@
+// internal/game/upgrades.go - Spending gold and cards on troop and tower levels
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"tcr-game/internal/models"
+)
+
+// UpgradeRequest names one troop or tower to raise by a level
+type UpgradeRequest struct {
+	Kind      models.UpgradeKind `json:"kind"`
+	TroopID   string             `json:"troop_id,omitempty"`
+	TowerType models.TowerType   `json:"tower_type,omitempty"`
+}
+
+type UpgradeResult struct {
+	Kind      models.UpgradeKind `json:"kind"`
+	TroopID   string             `json:"troop_id,omitempty"`
+	TowerType models.TowerType   `json:"tower_type,omitempty"`
+	Level     int                `json:"level"`
+	Cost      models.UpgradeCost `json:"cost"`
+	Gold      int                `json:"gold"`  // gold left after the upgrade
+	Cards     int                `json:"cards"` // cards of the troop left after the upgrade
+}
+
+// UpgradeOption is the current level of a troop or tower and what the next
+// level costs. Next is nil at the highest level.
+type UpgradeOption struct {
+	TroopID    string              `json:"troop_id,omitempty"`
+	TowerType  models.TowerType    `json:"tower_type,omitempty"`
+	Level      int                 `json:"level"`
+	Locked     bool                `json:"locked,omitempty"`
+	Next       *models.UpgradeCost `json:"next,omitempty"`
+	Affordable bool                `json:"affordable"`
+}
+
+type UpgradeOptions struct {
+	Gold   int             `json:"gold"`
+	Cards  map[string]int  `json:"cards"`
+	Troops []UpgradeOption `json:"troops"`
+	Towers []UpgradeOption `json:"towers"`
+}
+
+// upgrades returns the balance's upgrade catalogue, or the default one
+func (b *Balance) upgrades() *models.UpgradeCatalogue {
+	if b.Upgrades == nil {
+		return models.DefaultUpgradeCatalogue()
+	}
+	return b.Upgrades
+}
+
+// Upgrade checks the request against the balance and the stored player
+// record, deducts the cost and saves the new level. It shares the pipeline
+// lock so an upgrade never races with match rewards for the same player.
+func (pp *ProgressionPipeline) Upgrade(playerID string, request UpgradeRequest, balance *Balance) (*UpgradeResult, error) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	player, err := pp.storage.LoadPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{Kind: request.Kind}
+	var current int
+
+	switch request.Kind {
+	case models.UpgradeTroop:
+		troop, exists := balance.Troops.Get(request.TroopID)
+		if !exists {
+			return nil, fmt.Errorf("unknown troop: %s", request.TroopID)
+		}
+		if player.Level < troop.UnlockLevel {
+			return nil, fmt.Errorf("%s unlocks at level %d", troop.Name, troop.UnlockLevel)
+		}
+		result.TroopID = troop.ID
+		current = player.GetTroopLevel(troop.ID)
+	case models.UpgradeTower:
+		if _, exists := towerSpecs(balance)[request.TowerType]; !exists {
+			return nil, fmt.Errorf("unknown tower type: %s", request.TowerType)
+		}
+		result.TowerType = request.TowerType
+		current = player.GetTowerLevel(request.TowerType)
+	default:
+		return nil, errors.New("invalid upgrade kind")
+	}
+
+	cost, ok := balance.upgrades().NextCost(request.Kind, current)
+	if !ok {
+		return nil, errors.New("already at the highest level")
+	}
+	if player.Gold < cost.Gold {
+		return nil, fmt.Errorf("not enough gold: need %d, have %d", cost.Gold, player.Gold)
+	}
+	if request.Kind == models.UpgradeTroop && player.Cards[result.TroopID] < cost.Cards {
+		return nil, fmt.Errorf("not enough cards: need %d, have %d", cost.Cards, player.Cards[result.TroopID])
+	}
+
+	player.Gold -= cost.Gold
+	if request.Kind == models.UpgradeTroop {
+		if player.TroopLevels == nil {
+			player.TroopLevels = make(map[string]int)
+		}
+		player.AddCards(result.TroopID, -cost.Cards)
+		player.TroopLevels[result.TroopID] = cost.Level
+		result.Cards = player.Cards[result.TroopID]
+	} else {
+		if player.TowerLevels == nil {
+			player.TowerLevels = make(map[models.TowerType]int)
+		}
+		player.TowerLevels[result.TowerType] = cost.Level
+	}
+
+	if err := pp.storage.SavePlayer(player); err != nil {
+		return nil, err
+	}
+
+	result.Level = cost.Level
+	result.Cost = cost
+	result.Gold = player.Gold
+	return result, nil
+}
+
+// UpgradeOptions lists every troop and tower the player could upgrade
+func (pp *ProgressionPipeline) UpgradeOptions(playerID string, balance *Balance) (*UpgradeOptions, error) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	player, err := pp.storage.LoadPlayer(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogue := balance.upgrades()
+	options := &UpgradeOptions{
+		Gold:   player.Gold,
+		Cards:  player.Cards,
+		Troops: make([]UpgradeOption, 0, balance.Troops.Len()),
+		Towers: make([]UpgradeOption, 0),
+	}
+	if options.Cards == nil {
+		options.Cards = make(map[string]int)
+	}
+
+	for _, troop := range balance.Troops.All() {
+		option := UpgradeOption{
+			TroopID: troop.ID,
+			Level:   player.GetTroopLevel(troop.ID),
+			Locked:  player.Level < troop.UnlockLevel,
+		}
+		if cost, ok := catalogue.NextCost(models.UpgradeTroop, option.Level); ok {
+			option.Next = &cost
+			option.Affordable = !option.Locked && player.Gold >= cost.Gold && player.Cards[troop.ID] >= cost.Cards
+		}
+		options.Troops = append(options.Troops, option)
+	}
+
+	specs := towerSpecs(balance)
+	towerTypes := make([]models.TowerType, 0, len(specs))
+	for towerType := range specs {
+		towerTypes = append(towerTypes, towerType)
+	}
+	sort.Slice(towerTypes, func(i, j int) bool { return towerTypes[i] < towerTypes[j] })
+	for _, towerType := range towerTypes {
+		option := UpgradeOption{
+			TowerType: towerType,
+			Level:     player.GetTowerLevel(towerType),
+		}
+		if cost, ok := catalogue.NextCost(models.UpgradeTower, option.Level); ok {
+			option.Next = &cost
+			option.Affordable = player.Gold >= cost.Gold
+		}
+		options.Towers = append(options.Towers, option)
+	}
+
+	return options, nil
+}
+
+func towerSpecs(balance *Balance) map[models.TowerType]models.TowerSpec {
+	if balance.Towers == nil {
+		return models.DefaultTowerCatalogue().Specs
+	}
+	return balance.Towers.Specs
+}
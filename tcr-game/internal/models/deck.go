@@ -0,0 +1,85 @@
+// internal/models/deck.go - Player decks
+package models
+
+import "fmt"
+
+const (
+	// DeckSize is the number of different troops in a deck
+	DeckSize = 8
+	// HandSize is the number of deck cards playable at once in enhanced mode
+	HandSize = 4
+	// DeckSlots is the number of decks a player can keep
+	DeckSlots = 3
+)
+
+type Deck struct {
+	Name  string   `json:"name"`
+	Cards []string `json:"cards"` // troop IDs, dealt in this order
+}
+
+// ValidateDeck checks that cards are DeckSize different troops from the
+// catalogue, all unlocked at the player's level
+func ValidateDeck(cards []string, catalogue *TroopCatalogue, playerLevel int) error {
+	if len(cards) != DeckSize {
+		return fmt.Errorf("a deck needs %d cards, got %d", DeckSize, len(cards))
+	}
+
+	seen := make(map[string]bool, len(cards))
+	for _, id := range cards {
+		troop, exists := catalogue.Get(id)
+		if !exists {
+			return fmt.Errorf("unknown troop: %s", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%s is in the deck twice", troop.Name)
+		}
+		if playerLevel < troop.UnlockLevel {
+			return fmt.Errorf("%s unlocks at level %d", troop.Name, troop.UnlockLevel)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// DefaultDeck is the first DeckSize troops of the catalogue the player has
+// unlocked, used until the player picks a deck of their own
+func DefaultDeck(catalogue *TroopCatalogue, playerLevel int) []string {
+	cards := make([]string, 0, DeckSize)
+	for _, troop := range catalogue.All() {
+		if len(cards) == DeckSize {
+			break
+		}
+		if playerLevel >= troop.UnlockLevel {
+			cards = append(cards, troop.ID)
+		}
+	}
+	return cards
+}
+
+// ActiveDeck returns the cards of the player's selected deck, or nil when
+// the player has not built one
+func (p *Player) ActiveDeck() []string {
+	if p.ActiveDeckSlot < 0 || p.ActiveDeckSlot >= len(p.Decks) {
+		return nil
+	}
+	return p.Decks[p.ActiveDeckSlot].Cards
+}
+
+// DeckTroops returns the troops of the player's active deck at the player's
+// levels. A deck that no longer validates against the catalogue, for
+// instance after a balance change removed a troop, falls back to the
+// default deck.
+func (p *Player) DeckTroops(catalogue *TroopCatalogue) []*Troop {
+	cards := p.ActiveDeck()
+	if ValidateDeck(cards, catalogue, p.Level) != nil {
+		cards = DefaultDeck(catalogue, p.Level)
+	}
+
+	troops := make([]*Troop, 0, len(cards))
+	for _, id := range cards {
+		troop, _ := catalogue.Get(id)
+		troop.ApplyLevel(p.GetTroopLevel(id))
+		troops = append(troops, troop)
+	}
+	return troops
+}
@@ -8,12 +8,16 @@ type GameMode string
 const (
 	SimpleMode   GameMode = "simple"
 	EnhancedMode GameMode = "enhanced"
+	DraftMode    GameMode = "draft"
+	TeamMode     GameMode = "team"
+	FFAMode      GameMode = "ffa"
 )
 
 type GameState string
 
 const (
 	Waiting    GameState = "waiting"
+	Drafting   GameState = "drafting"
 	InProgress GameState = "in_progress"
 	Finished   GameState = "finished"
 )
@@ -23,12 +27,20 @@ type Game struct {
 	Mode        GameMode         `json:"mode"`
 	State       GameState        `json:"state"`
 	Players     []*Player        `json:"players"`
+	MaxPlayers  int              `json:"max_players"`
+	TeamSize    int              `json:"team_size,omitempty"` // players per side, 0 when everyone plays alone
 	CurrentTurn int              `json:"current_turn"`
 	StartTime   time.Time        `json:"start_time"`
 	EndTime     *time.Time       `json:"end_time,omitempty"`
 	Duration    int              `json:"duration"` // seconds for enhanced mode
 	Winner      *Player          `json:"winner,omitempty"`
+	Places      map[string]int   `json:"places,omitempty"` // player ID -> finishing place, 1 for the winner
 	Events      []GameEvent      `json:"events"`
+	Seed        int64            `json:"seed"`
+	Replay      *Replay          `json:"-"`
+	TowerCatalogue *TowerCatalogue `json:"-"`
+	BalanceVersion int             `json:"balance_version"` // balance data the game was created with
+	rng         *GameRNG
 }
 
 type GameEvent struct {
@@ -39,18 +51,48 @@ type GameEvent struct {
 }
 
 func NewGame(id string, mode GameMode) *Game {
+	return NewGameWithSeed(id, mode, time.Now().UnixNano())
+}
+
+// NewGameWithSeed creates a game whose random decisions are fully determined by seed
+func NewGameWithSeed(id string, mode GameMode, seed int64) *Game {
 	return &Game{
 		ID:          id,
 		Mode:        mode,
 		State:       Waiting,
 		Players:     make([]*Player, 0, 2),
+		MaxPlayers:  2,
 		CurrentTurn: 0,
 		Events:      make([]GameEvent, 0),
+		Seed:        seed,
+		rng:         NewGameRNG(seed),
+	}
+}
+
+// Capacity returns how many players the game needs to start
+func (g *Game) Capacity() int {
+	if g.MaxPlayers <= 0 {
+		return 2
+	}
+	return g.MaxPlayers
+}
+
+// IsFull reports whether every seat of the game is taken
+func (g *Game) IsFull() bool {
+	return len(g.Players) >= g.Capacity()
+}
+
+// RNG returns the game's random source. Games loaded from storage get a
+// fresh generator from their recorded seed.
+func (g *Game) RNG() *GameRNG {
+	if g.rng == nil {
+		g.rng = NewGameRNG(g.Seed)
 	}
+	return g.rng
 }
 
 func (g *Game) AddPlayer(player *Player) bool {
-	if len(g.Players) >= 2 {
+	if g.IsFull() {
 		return false
 	}
 	g.Players = append(g.Players, player)
@@ -61,9 +103,12 @@ func (g *Game) Start() {
 	g.State = InProgress
 	g.StartTime = time.Now()
 	
-	// Initialize towers for both players
+	// Initialize towers for every player from the game's tower catalogue
 	for _, player := range g.Players {
-		player.InitializeTowers(nil) // TODO: Pass actual tower specs
+		player.InitializeTowers(g.TowerCatalogue)
+	}
+	if g.TeamSize > 1 {
+		g.divideTeamTowers()
 	}
 }
 
@@ -81,11 +126,10 @@ func (g *Game) IsFinished() bool {
 	return g.State == Finished
 }
 
+// GetOpponent returns the first player on another side
 func (g *Game) GetOpponent(playerID string) *Player {
-	for _, player := range g.Players {
-		if player.ID != playerID {
-			return player
-		}
+	if opponents := g.Opponents(playerID); len(opponents) > 0 {
+		return opponents[0]
 	}
 	return nil
 }
\ No newline at end of file
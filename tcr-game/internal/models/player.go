@@ -12,6 +12,11 @@ type Player struct {
 	TroopLevels map[string]int    `json:"troop_levels"`
 	TowerLevels map[TowerType]int `json:"tower_levels"`
 	Stats       PlayerStats       `json:"stats"`
+	Ratings     map[GameMode]*Rating `json:"ratings"`
+	Gold        int               `json:"gold"`
+	Cards       map[string]int    `json:"cards"` // troop ID -> unspent cards
+	Decks       []Deck            `json:"decks"`
+	ActiveDeckSlot int            `json:"active_deck"`
 	Towers      []*Tower          `json:"towers"`
 	AvailableTroops []*Troop      `json:"available_troops"`
 	Mana        int               `json:"mana"`
@@ -36,7 +41,9 @@ func NewPlayer(id, username, password string) *Player {
 		TroopLevels: make(map[string]int),
 		TowerLevels: make(map[TowerType]int),
 		Stats:       PlayerStats{},
-		Towers:      make([]*Tower, 3),
+		Ratings:     make(map[GameMode]*Rating),
+		Cards:       make(map[string]int),
+		Towers:      make([]*Tower, 0),
 		AvailableTroops: make([]*Troop, 0),
 		Mana:        5,
 		MaxMana:     10,
@@ -44,10 +51,44 @@ func NewPlayer(id, username, password string) *Player {
 	}
 }
 
+// Record counts the outcome of a finished game
+func (s *PlayerStats) Record(result MatchResult) {
+	s.GamesPlayed++
+	
+	switch result {
+	case ResultWin:
+		s.GamesWon++
+	case ResultDraw:
+		s.GamesDrawn++
+	default:
+		s.GamesLost++
+	}
+}
+
+// RatingFor returns the player's rating in a mode, starting a new one if needed
+func (p *Player) RatingFor(mode GameMode) *Rating {
+	if p.Ratings == nil {
+		p.Ratings = make(map[GameMode]*Rating)
+	}
+	rating, exists := p.Ratings[mode]
+	if !exists {
+		rating = NewRating()
+		p.Ratings[mode] = rating
+	}
+	return rating
+}
+
+// AddCards credits cards of a troop
+func (p *Player) AddCards(troopID string, count int) {
+	if p.Cards == nil {
+		p.Cards = make(map[string]int)
+	}
+	p.Cards[troopID] += count
+}
+
 func (p *Player) AddExperience(exp int) {
 	p.Experience += exp
-	// Simple leveling: every 100 EXP = 1 level
-	newLevel := (p.Experience / 100) + 1
+	newLevel := LevelForExp(p.Experience)
 	if newLevel > p.Level {
 		p.Level = newLevel
 	}
@@ -93,16 +134,40 @@ func (p *Player) GetTroopLevel(troopID string) int {
 	return 1
 }
 
-func (p *Player) InitializeTowers(towerSpecs map[string]interface{}) {
-	// Left Guard Tower
-	p.Towers[0] = NewTower(GuardTower, "Left Guard Tower", 300, 20, 10, 0.05, "Left defensive tower", 0)
-	p.Towers[0].ApplyLevel(p.GetTowerLevel(GuardTower))
-	
-	// Right Guard Tower
-	p.Towers[1] = NewTower(GuardTower, "Right Guard Tower", 300, 20, 10, 0.05, "Right defensive tower", 1)
-	p.Towers[1].ApplyLevel(p.GetTowerLevel(GuardTower))
+// InitializeTowers builds the player's towers from the catalogue layout,
+// falling back to the default layout when no catalogue is given
+func (p *Player) InitializeTowers(catalogue *TowerCatalogue) {
+	if catalogue == nil {
+		catalogue = DefaultTowerCatalogue()
+	}
 	
-	// King Tower
-	p.Towers[2] = NewTower(KingTower, "King Tower", 500, 25, 15, 0.1, "Main tower", 2)
-	p.Towers[2].ApplyLevel(p.GetTowerLevel(KingTower))
+	p.Towers = make([]*Tower, len(catalogue.Layout))
+	for i, placement := range catalogue.Layout {
+		spec := catalogue.Specs[placement.Type]
+		
+		name := placement.Name
+		if name == "" {
+			name = spec.Name
+		}
+		description := placement.Description
+		if description == "" {
+			description = spec.Description
+		}
+		
+		p.Towers[i] = NewTower(placement.Type, name, spec.HP, spec.Attack, spec.Defense, spec.CritChance, description, placement.Position)
+		if spec.Growth != nil {
+			p.Towers[i].Growth = *spec.Growth
+		}
+		p.Towers[i].ApplyLevel(p.GetTowerLevel(placement.Type))
+	}
+}
+
+// KingTower returns the player's king tower, or nil before towers are initialized
+func (p *Player) KingTower() *Tower {
+	for _, tower := range p.Towers {
+		if tower != nil && tower.Type == KingTower {
+			return tower
+		}
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+// internal/models/rating.go - Player skill ratings
+package models
+
+import "time"
+
+const (
+	// DefaultRating is the rating a player starts with in every mode
+	DefaultRating = 1200
+	// RatingHistoryLimit caps how many past matches a rating remembers
+	RatingHistoryLimit = 100
+)
+
+type MatchResult string
+
+const (
+	ResultWin  MatchResult = "win"
+	ResultLoss MatchResult = "loss"
+	ResultDraw MatchResult = "draw"
+)
+
+// Rating is a player's Elo rating in one game mode
+type Rating struct {
+	Value   int            `json:"value"`
+	Peak    int            `json:"peak"`
+	Games   int            `json:"games"`
+	History []RatingChange `json:"history"`
+}
+
+// RatingChange records how one match moved a rating
+type RatingChange struct {
+	GameID         string      `json:"game_id"`
+	OpponentID     string      `json:"opponent_id"`
+	OpponentRating int         `json:"opponent_rating"`
+	Result         MatchResult `json:"result"`
+	Before         int         `json:"before"`
+	After          int         `json:"after"`
+	Timestamp      time.Time   `json:"timestamp"`
+}
+
+func NewRating() *Rating {
+	return &Rating{
+		Value:   DefaultRating,
+		Peak:    DefaultRating,
+		History: make([]RatingChange, 0),
+	}
+}
+
+// Apply moves the rating to change.After and appends the change to the history
+func (r *Rating) Apply(change RatingChange) {
+	r.Value = change.After
+	if r.Value > r.Peak {
+		r.Peak = r.Value
+	}
+	r.Games++
+
+	r.History = append(r.History, change)
+	if len(r.History) > RatingHistoryLimit {
+		r.History = r.History[len(r.History)-RatingHistoryLimit:]
+	}
+}
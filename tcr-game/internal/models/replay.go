@@ -0,0 +1,133 @@
+// internal/models/replay.go - Match replay log
+package models
+
+import "time"
+
+// ReplayVersion is bumped whenever the replay format or the rules it
+// re-simulates change incompatibly
+const ReplayVersion = 5
+
+type ReplayActionKind string
+
+const (
+	ReplaySimpleAction   ReplayActionKind = "simple"
+	ReplayEnhancedAction ReplayActionKind = "enhanced"
+)
+
+type Replay struct {
+	Version        int            `json:"version"`
+	GameID         string         `json:"game_id"`
+	Mode           GameMode       `json:"mode"`
+	TeamSize       int            `json:"team_size,omitempty"`
+	Seed           int64          `json:"seed"`
+	BalanceVersion int            `json:"balance_version"`
+	RNGDraws       int64          `json:"rng_draws"`       // values drawn before the first action
+	Ticks          int            `json:"ticks,omitempty"` // arena ticks simulated in enhanced mode
+	SuddenDeathTick int           `json:"sudden_death_tick,omitempty"` // ticks completed when sudden death began
+	Tiebreaker     bool           `json:"tiebreaker,omitempty"`        // the game was decided on tower HP after overtime
+	StartTime      time.Time      `json:"start_time"`
+	EndTime        *time.Time     `json:"end_time,omitempty"`
+	Players        []ReplayPlayer `json:"players"`
+	Actions        []ReplayAction `json:"actions"`
+	Result         *ReplayResult  `json:"result,omitempty"`
+}
+
+// ReplayPlayer is a player's troops and towers as they were when the match started
+type ReplayPlayer struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Troops   []Troop `json:"troops"`
+	Towers   []Tower `json:"towers"`
+}
+
+type ReplayAction struct {
+	Sequence    int              `json:"sequence"`
+	Kind        ReplayActionKind `json:"kind"`
+	PlayerID    string           `json:"player_id"`
+	Type        string           `json:"type"`
+	TroopID     string           `json:"troop_id"`
+	TargetPlayer string          `json:"target_player,omitempty"` // player whose tower was attacked
+	TargetTower int              `json:"target_tower"`
+	Lane        int              `json:"lane,omitempty"` // lane an enhanced troop was spawned on
+	Tick        int              `json:"tick,omitempty"` // arena ticks completed before an enhanced spawn
+	Mana        int              `json:"mana,omitempty"` // attacker's mana when an enhanced action was accepted
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+type ReplayResult struct {
+	WinnerID string           `json:"winner_id"`
+	TowerHP  map[string][]int `json:"tower_hp"`
+}
+
+// BeginReplay snapshots the starting troops and towers and starts a new replay log
+func (g *Game) BeginReplay() {
+	players := make([]ReplayPlayer, len(g.Players))
+	for i, player := range g.Players {
+		snapshot := ReplayPlayer{
+			ID:       player.ID,
+			Username: player.Username,
+			Troops:   make([]Troop, 0, len(player.AvailableTroops)),
+			Towers:   make([]Tower, 0, len(player.Towers)),
+		}
+		for _, troop := range player.AvailableTroops {
+			snapshot.Troops = append(snapshot.Troops, *troop)
+		}
+		for _, tower := range player.Towers {
+			snapshot.Towers = append(snapshot.Towers, *tower)
+		}
+		players[i] = snapshot
+	}
+
+	g.Replay = &Replay{
+		Version:        ReplayVersion,
+		GameID:         g.ID,
+		Mode:           g.Mode,
+		TeamSize:       g.TeamSize,
+		Seed:           g.Seed,
+		BalanceVersion: g.BalanceVersion,
+		RNGDraws:       g.RNG().Draws(),
+		StartTime:      g.StartTime,
+		Players:        players,
+		Actions:        make([]ReplayAction, 0),
+	}
+}
+
+// RecordAction appends an accepted action to the replay log
+func (g *Game) RecordAction(action ReplayAction) {
+	if g.Replay == nil {
+		return
+	}
+	action.Sequence = len(g.Replay.Actions) + 1
+	if action.Timestamp.IsZero() {
+		action.Timestamp = time.Now()
+	}
+	g.Replay.Actions = append(g.Replay.Actions, action)
+}
+
+// FinishReplay records the final outcome once the game has ended
+func (g *Game) FinishReplay() {
+	if g.Replay == nil {
+		return
+	}
+	g.Replay.EndTime = g.EndTime
+	g.Replay.Result = NewReplayResult(g)
+}
+
+func NewReplayResult(game *Game) *ReplayResult {
+	result := &ReplayResult{
+		TowerHP: make(map[string][]int),
+	}
+	if game.Winner != nil {
+		result.WinnerID = game.Winner.ID
+	}
+	for _, player := range game.Players {
+		hp := make([]int, len(player.Towers))
+		for i, tower := range player.Towers {
+			if tower != nil {
+				hp[i] = tower.HP
+			}
+		}
+		result.TowerHP[player.ID] = hp
+	}
+	return result
+}
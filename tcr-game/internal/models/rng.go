@@ -0,0 +1,81 @@
+// internal/models/rng.go - Per-game random number generation
+package models
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// RandomSource is the subset of math/rand used by combat and troop selection.
+// Every random decision in a match goes through one so the match can be
+// reproduced from its seed.
+type RandomSource interface {
+	Float64() float64
+	Intn(n int) int
+}
+
+// GameRNG is a seedable RandomSource that is safe for concurrent use. It
+// counts the values drawn from its source so a replay can fast-forward to
+// the same position.
+type GameRNG struct {
+	seed   int64
+	source *countingSource
+	rng    *rand.Rand
+	mutex  sync.Mutex
+}
+
+type countingSource struct {
+	src   rand.Source
+	draws int64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+func NewGameRNG(seed int64) *GameRNG {
+	source := &countingSource{src: rand.NewSource(seed)}
+	return &GameRNG{
+		seed:   seed,
+		source: source,
+		rng:    rand.New(source),
+	}
+}
+
+func (r *GameRNG) Seed() int64 {
+	return r.seed
+}
+
+func (r *GameRNG) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rng.Float64()
+}
+
+func (r *GameRNG) Intn(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rng.Intn(n)
+}
+
+// Draws returns how many values have been taken from the underlying source
+func (r *GameRNG) Draws() int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.source.draws
+}
+
+// Skip discards n values from the underlying source
+func (r *GameRNG) Skip(n int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i := int64(0); i < n; i++ {
+		r.source.Int63()
+	}
+}
@@ -0,0 +1,76 @@
+// internal/models/scaling.go - Level scaling for unit stats and player experience
+package models
+
+// Stats are the level-dependent stats of a troop or tower
+type Stats struct {
+	HP      int `json:"hp"`
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+}
+
+// StatGrowth is how much of the base value each stat gains per level above 1
+type StatGrowth struct {
+	HP      float64 `json:"hp"`
+	Attack  float64 `json:"attack"`
+	Defense float64 `json:"defense"`
+}
+
+// Valid reports whether no stat shrinks with level
+func (g StatGrowth) Valid() bool {
+	return g.HP >= 0 && g.Attack >= 0 && g.Defense >= 0
+}
+
+// DefaultStatGrowth is 10% of the base value per level for every stat
+var DefaultStatGrowth = StatGrowth{HP: 0.1, Attack: 0.1, Defense: 0.1}
+
+// ScaleStats derives a unit's stats at a level from its base stats. Growth
+// is linear and always applied to the base values, so the result depends
+// only on the level and never on previous scaling.
+func ScaleStats(base Stats, growth StatGrowth, level int) Stats {
+	if level < 1 {
+		level = 1
+	}
+	steps := float64(level - 1)
+
+	return Stats{
+		HP:      int(float64(base.HP) * (1 + growth.HP*steps)),
+		Attack:  int(float64(base.Attack) * (1 + growth.Attack*steps)),
+		Defense: int(float64(base.Defense) * (1 + growth.Defense*steps)),
+	}
+}
+
+const (
+	// BaseLevelExp is the EXP needed to go from level 1 to 2
+	BaseLevelExp = 100
+	// LevelExpGrowth is how much more EXP each following level needs
+	LevelExpGrowth = 0.1
+)
+
+// ExpToNextLevel is the EXP needed to go from level to level+1. It is the
+// one EXP curve: player leveling and level progress displays both use it.
+func ExpToNextLevel(level int) int {
+	required := BaseLevelExp
+	for i := 1; i < level; i++ {
+		required = int(float64(required) * (1 + LevelExpGrowth))
+	}
+	return required
+}
+
+// LevelProgress returns the level reached with a total amount of EXP, the
+// EXP earned within that level and the EXP that level needs in total
+func LevelProgress(exp int) (level, into, needed int) {
+	level = 1
+	needed = ExpToNextLevel(level)
+	for exp >= needed {
+		exp -= needed
+		level++
+		needed = int(float64(needed) * (1 + LevelExpGrowth))
+	}
+	return level, exp, needed
+}
+
+// LevelForExp returns the level reached with a total amount of EXP
+func LevelForExp(exp int) int {
+	level, _, _ := LevelProgress(exp)
+	return level
+}
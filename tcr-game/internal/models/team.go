@@ -0,0 +1,128 @@
+// internal/models/team.go - Sides of a game
+package models
+
+// Teams returns how many sides the game is played between. Without a team
+// size every player is a side of their own.
+func (g *Game) Teams() int {
+	if g.TeamSize <= 1 {
+		return g.Capacity()
+	}
+	return g.Capacity() / g.TeamSize
+}
+
+// TeamOf returns the side a player is on, or -1 for players not in the
+// game. Players join the sides in turn, so the turn order alternates
+// between teams.
+func (g *Game) TeamOf(playerID string) int {
+	for i, player := range g.Players {
+		if player.ID == playerID {
+			return i % g.Teams()
+		}
+	}
+	return -1
+}
+
+// TeamMembers returns the players on a side in turn order
+func (g *Game) TeamMembers(team int) []*Player {
+	members := make([]*Player, 0, g.TeamSize)
+	for i, player := range g.Players {
+		if i%g.Teams() == team {
+			members = append(members, player)
+		}
+	}
+	return members
+}
+
+// Opponents returns the players on every other side
+func (g *Game) Opponents(playerID string) []*Player {
+	team := g.TeamOf(playerID)
+	opponents := make([]*Player, 0, len(g.Players))
+	for i, player := range g.Players {
+		if i%g.Teams() != team {
+			opponents = append(opponents, player)
+		}
+	}
+	return opponents
+}
+
+// TeamTowers returns the towers of a side, counting shared towers once
+func (g *Game) TeamTowers(team int) []*Tower {
+	towers := make([]*Tower, 0)
+	seen := make(map[*Tower]bool)
+	for _, player := range g.TeamMembers(team) {
+		for _, tower := range player.Towers {
+			if tower != nil && !seen[tower] {
+				seen[tower] = true
+				towers = append(towers, tower)
+			}
+		}
+	}
+	return towers
+}
+
+// TeamStanding reports whether a side is still in the game: its king tower
+// stands and it has not been given a place below first. Sides whose towers
+// are not built yet are standing.
+func (g *Game) TeamStanding(team int) bool {
+	for _, player := range g.TeamMembers(team) {
+		if king := player.KingTower(); king != nil && !king.IsAlive() {
+			return false
+		}
+		if g.PlaceOf(player.ID) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// PlaceTeam records the finishing place of every player on a side
+func (g *Game) PlaceTeam(team, place int) {
+	if g.Places == nil {
+		g.Places = make(map[string]int)
+	}
+	for _, player := range g.TeamMembers(team) {
+		g.Places[player.ID] = place
+	}
+}
+
+// PlaceOf returns a player's finishing place, or 0 while they have none
+func (g *Game) PlaceOf(playerID string) int {
+	return g.Places[playerID]
+}
+
+// divideTeamTowers gives each teammate the guard towers on their own lane,
+// then has the team share its first player's king tower
+func (g *Game) divideTeamTowers() {
+	for i, player := range g.Players {
+		lane := (i / g.Teams()) % 2
+		towers := make([]*Tower, 0, len(player.Towers))
+		for _, tower := range player.Towers {
+			if tower.Type == KingTower || tower.Position == lane {
+				towers = append(towers, tower)
+			}
+		}
+		player.Towers = towers
+	}
+	g.ShareTeamKings()
+}
+
+// ShareTeamKings points every teammate's king tower at their team's first
+// player's, so the side falls with a single king. Replays call it after
+// rebuilding a team game's towers from their snapshots.
+func (g *Game) ShareTeamKings() {
+	if g.TeamSize <= 1 {
+		return
+	}
+	for i, player := range g.Players {
+		captain := g.Players[i%g.Teams()]
+		if captain == player {
+			continue
+		}
+		king := captain.KingTower()
+		for j, tower := range player.Towers {
+			if tower != nil && tower.Type == KingTower && king != nil {
+				player.Towers[j] = king
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 // internal/models/tower.go - Tower model
 package models
 
+import "fmt"
+
 type TowerType string
 
 const (
@@ -8,6 +10,10 @@ const (
 	GuardTower TowerType = "guard_tower"
 )
 
+// ArenaLanes is how many lanes the arena has. Towers other than the king
+// stand on one of them, numbered from 0 (left).
+const ArenaLanes = 2
+
 type Tower struct {
 	Type        TowerType `json:"type"`
 	Name        string    `json:"name"`
@@ -18,7 +24,99 @@ type Tower struct {
 	CritChance  float64   `json:"crit_chance"`
 	Description string    `json:"description"`
 	Level       int       `json:"level"`
-	Position    int       `json:"position"` // 0=left guard, 1=right guard, 2=king
+	Position    int       `json:"position"` // lane a tower other than the king stands on (0=left, 1=right)
+	Base        Stats      `json:"base"`   // stats at level 1
+	Growth      StatGrowth `json:"growth"` // per-level growth from towers.json
+}
+
+// TowerSpec holds the base stats of a tower type. Without a growth curve
+// the tower scales by DefaultStatGrowth.
+type TowerSpec struct {
+	Name        string      `json:"name"`
+	HP          int         `json:"hp"`
+	Attack      int         `json:"attack"`
+	Defense     int         `json:"defense"`
+	CritChance  float64     `json:"crit_chance"`
+	Description string      `json:"description"`
+	Growth      *StatGrowth `json:"growth,omitempty"`
+}
+
+// TowerPlacement is one tower of an arena layout. Name and description
+// default to the spec's when empty.
+type TowerPlacement struct {
+	Type        TowerType `json:"type"`
+	Name        string    `json:"name,omitempty"`
+	Position    int       `json:"position"`
+	Description string    `json:"description,omitempty"`
+}
+
+// TowerCatalogue is the tower data loaded from towers.json. In simple mode
+// towers must be destroyed in layout order.
+type TowerCatalogue struct {
+	Specs  map[TowerType]TowerSpec `json:"towers"`
+	Layout []TowerPlacement        `json:"layout"`
+}
+
+// DefaultTowerCatalogue is the classic two guards and a king layout
+func DefaultTowerCatalogue() *TowerCatalogue {
+	return &TowerCatalogue{
+		Specs: map[TowerType]TowerSpec{
+			KingTower:  {Name: "King Tower", HP: 500, Attack: 25, Defense: 15, CritChance: 0.1, Description: "Main tower"},
+			GuardTower: {Name: "Guard Tower", HP: 300, Attack: 20, Defense: 10, CritChance: 0.05, Description: "Defensive tower"},
+		},
+		Layout: []TowerPlacement{
+			{Type: GuardTower, Name: "Left Guard Tower", Position: 0, Description: "Left defensive tower"},
+			{Type: GuardTower, Name: "Right Guard Tower", Position: 1, Description: "Right defensive tower"},
+			{Type: KingTower, Name: "King Tower", Position: 2, Description: "Main tower"},
+		},
+	}
+}
+
+// Validate checks the specs and that the layout has exactly one king tower,
+// placed last, with every other tower on a lane of the arena. Tower types
+// other than the king and guard towers stand on their lane like guards.
+func (c *TowerCatalogue) Validate() error {
+	for towerType, spec := range c.Specs {
+		if towerType == "" {
+			return fmt.Errorf("tower type must not be empty")
+		}
+		if spec.HP <= 0 {
+			return fmt.Errorf("tower %s: hp must be positive", towerType)
+		}
+		if spec.Attack < 0 || spec.Defense < 0 {
+			return fmt.Errorf("tower %s: attack and defense must not be negative", towerType)
+		}
+		if spec.CritChance < 0 || spec.CritChance > 1 {
+			return fmt.Errorf("tower %s: crit_chance must be between 0 and 1", towerType)
+		}
+		if spec.Growth != nil && !spec.Growth.Valid() {
+			return fmt.Errorf("tower %s: growth must not be negative", towerType)
+		}
+	}
+	
+	if len(c.Layout) == 0 {
+		return fmt.Errorf("tower layout is empty")
+	}
+	
+	kings := 0
+	for i, placement := range c.Layout {
+		if _, exists := c.Specs[placement.Type]; !exists {
+			return fmt.Errorf("layout[%d]: no spec for tower type %s", i, placement.Type)
+		}
+		if placement.Type == KingTower {
+			kings++
+			if i != len(c.Layout)-1 {
+				return fmt.Errorf("layout[%d]: king tower must be the last tower", i)
+			}
+		} else if placement.Position < 0 || placement.Position >= ArenaLanes {
+			return fmt.Errorf("layout[%d]: %s position must be a lane from 0 to %d", i, placement.Type, ArenaLanes-1)
+		}
+	}
+	if kings != 1 {
+		return fmt.Errorf("tower layout needs exactly one king tower, got %d", kings)
+	}
+	
+	return nil
 }
 
 func NewTower(towerType TowerType, name string, hp, attack, defense int, critChance float64, description string, position int) *Tower {
@@ -33,6 +131,8 @@ func NewTower(towerType TowerType, name string, hp, attack, defense int, critCha
 		Description: description,
 		Level:       1,
 		Position:    position,
+		Base:        Stats{HP: hp, Attack: attack, Defense: defense},
+		Growth:      DefaultStatGrowth,
 	}
 }
 
@@ -42,13 +142,18 @@ func (t *Tower) ApplyLevel(level int) {
 	}
 	t.Level = level
 	
-	// Each level increases stats by 10%
-	multiplier := 1.0 + (0.1 * float64(level-1))
+	// Stats are derived from the base values, so applying a level twice
+	// does not compound. Units built without base stats start from their
+	// current ones.
+	if t.Base == (Stats{}) {
+		t.Base = Stats{HP: t.MaxHP, Attack: t.Attack, Defense: t.Defense}
+	}
+	stats := ScaleStats(t.Base, t.Growth, level)
 	
-	t.HP = int(float64(t.MaxHP) * multiplier)
-	t.MaxHP = t.HP
-	t.Attack = int(float64(t.Attack) * multiplier)
-	t.Defense = int(float64(t.Defense) * multiplier)
+	t.HP = stats.HP
+	t.MaxHP = stats.HP
+	t.Attack = stats.Attack
+	t.Defense = stats.Defense
 }
 
 func (t *Tower) IsAlive() bool {
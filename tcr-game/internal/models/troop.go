@@ -1,7 +1,8 @@
 // internal/models/troop.go - Troop model
 package models
 
-import "math/rand"
+// DefaultTroopSpeed is the arena distance a troop covers per tick unless troops.json says otherwise
+const DefaultTroopSpeed = 5
 
 type Troop struct {
 	ID          string  `json:"id"`
@@ -13,7 +14,52 @@ type Troop struct {
 	CritChance  float64 `json:"crit_chance"`
 	ManaCost    int     `json:"mana_cost"`
 	Description string  `json:"description"`
+	Speed       int     `json:"speed"`
+	UnlockLevel int     `json:"unlock_level"` // player level required to use the troop
 	Level       int     `json:"level"`
+	Base        Stats      `json:"base"`   // stats at level 1
+	Growth      StatGrowth `json:"growth"` // per-level growth from troops.json
+}
+
+// TroopCatalogue is the validated troop data loaded from troops.json, in file order
+type TroopCatalogue struct {
+	troops []*Troop
+	byID   map[string]*Troop
+}
+
+func NewTroopCatalogue(troops []*Troop) *TroopCatalogue {
+	catalogue := &TroopCatalogue{
+		troops: troops,
+		byID:   make(map[string]*Troop, len(troops)),
+	}
+	for _, troop := range troops {
+		catalogue.byID[troop.ID] = troop
+	}
+	return catalogue
+}
+
+// All returns a fresh copy of every troop template
+func (c *TroopCatalogue) All() []*Troop {
+	troops := make([]*Troop, len(c.troops))
+	for i, troop := range c.troops {
+		copied := *troop
+		troops[i] = &copied
+	}
+	return troops
+}
+
+// Get returns a copy of the troop template with the given ID
+func (c *TroopCatalogue) Get(id string) (*Troop, bool) {
+	troop, exists := c.byID[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *troop
+	return &copied, true
+}
+
+func (c *TroopCatalogue) Len() int {
+	return len(c.troops)
 }
 
 func NewTroop(id, name string, hp, attack, defense int, critChance float64, manaCost int, description string) *Troop {
@@ -27,7 +73,11 @@ func NewTroop(id, name string, hp, attack, defense int, critChance float64, mana
 		CritChance:  critChance,
 		ManaCost:    manaCost,
 		Description: description,
+		Speed:       DefaultTroopSpeed,
+		UnlockLevel: 1,
 		Level:       1,
+		Base:        Stats{HP: hp, Attack: attack, Defense: defense},
+		Growth:      DefaultStatGrowth,
 	}
 }
 
@@ -37,13 +87,18 @@ func (t *Troop) ApplyLevel(level int) {
 	}
 	t.Level = level
 	
-	// Each level increases stats by 10%
-	multiplier := 1.0 + (0.1 * float64(level-1))
+	// Stats are derived from the base values, so applying a level twice
+	// does not compound. Units built without base stats start from their
+	// current ones.
+	if t.Base == (Stats{}) {
+		t.Base = Stats{HP: t.MaxHP, Attack: t.Attack, Defense: t.Defense}
+	}
+	stats := ScaleStats(t.Base, t.Growth, level)
 	
-	t.HP = int(float64(t.MaxHP) * multiplier)
-	t.MaxHP = t.HP
-	t.Attack = int(float64(t.Attack) * multiplier)
-	t.Defense = int(float64(t.Defense) * multiplier)
+	t.HP = stats.HP
+	t.MaxHP = stats.HP
+	t.Attack = stats.Attack
+	t.Defense = stats.Defense
 }
 
 func (t *Troop) IsAlive() bool {
@@ -57,7 +112,11 @@ func (t *Troop) TakeDamage(damage int) {
 	}
 }
 
-func (t *Troop) AttackTarget(target Attackable) int {
+func (t *Troop) GetDefense() int {
+	return t.Defense
+}
+
+func (t *Troop) AttackTarget(target Attackable, rng RandomSource) int {
 	// Calculate base damage
 	baseDamage := t.Attack - target.GetDefense()
 	if baseDamage < 0 {
@@ -66,7 +125,7 @@ func (t *Troop) AttackTarget(target Attackable) int {
 	
 	// Check for critical hit
 	damage := baseDamage
-	if rand.Float64() < t.CritChance {
+	if rng.Float64() < t.CritChance {
 		damage = int(float64(baseDamage) * 1.2) // 20% crit bonus
 	}
 	
@@ -0,0 +1,97 @@
+// internal/models/upgrade.go - Upgrade costs and match currency
+package models
+
+import "fmt"
+
+type UpgradeKind string
+
+const (
+	UpgradeTroop UpgradeKind = "troop"
+	UpgradeTower UpgradeKind = "tower"
+)
+
+// UpgradeCost is what it takes to raise a troop or tower to Level
+type UpgradeCost struct {
+	Level int `json:"level"`
+	Gold  int `json:"gold"`
+	Cards int `json:"cards"` // cards of the troop being upgraded; towers need none
+}
+
+// MatchCurrency is the gold and troop cards a finished match pays out.
+// Cards are given for every troop the player brought to the match.
+type MatchCurrency struct {
+	GoldWin     int `json:"gold_win"`
+	GoldDraw    int `json:"gold_draw"`
+	GoldLoss    int `json:"gold_loss"`
+	CardsWin    int `json:"cards_win"`
+	CardsDraw   int `json:"cards_draw"`
+	CardsLoss   int `json:"cards_loss"`
+	LevelUpGold int `json:"level_up_gold"` // bonus for every player level gained
+}
+
+// UpgradeCatalogue lists upgrade costs level by level, starting at level 2
+type UpgradeCatalogue struct {
+	Rewards     MatchCurrency `json:"rewards"`
+	TroopLevels []UpgradeCost `json:"troop_levels"`
+	TowerLevels []UpgradeCost `json:"tower_levels"`
+}
+
+func DefaultUpgradeCatalogue() *UpgradeCatalogue {
+	return &UpgradeCatalogue{
+		Rewards: MatchCurrency{
+			GoldWin: 50, GoldDraw: 25, GoldLoss: 10,
+			CardsWin: 3, CardsDraw: 2, CardsLoss: 1,
+			LevelUpGold: 100,
+		},
+		TroopLevels: []UpgradeCost{
+			{Level: 2, Gold: 100, Cards: 5},
+			{Level: 3, Gold: 250, Cards: 10},
+			{Level: 4, Gold: 500, Cards: 20},
+			{Level: 5, Gold: 1000, Cards: 50},
+		},
+		TowerLevels: []UpgradeCost{
+			{Level: 2, Gold: 300},
+			{Level: 3, Gold: 800},
+			{Level: 4, Gold: 2000},
+		},
+	}
+}
+
+// Validate checks that costs are listed for consecutive levels from 2
+func (c *UpgradeCatalogue) Validate() error {
+	rewards := c.Rewards
+	if rewards.GoldWin < 0 || rewards.GoldDraw < 0 || rewards.GoldLoss < 0 ||
+		rewards.CardsWin < 0 || rewards.CardsDraw < 0 || rewards.CardsLoss < 0 || rewards.LevelUpGold < 0 {
+		return fmt.Errorf("rewards must not be negative")
+	}
+
+	for kind, costs := range map[UpgradeKind][]UpgradeCost{UpgradeTroop: c.TroopLevels, UpgradeTower: c.TowerLevels} {
+		for i, cost := range costs {
+			if cost.Level != i+2 {
+				return fmt.Errorf("%s level %d: levels must run consecutively from 2", kind, cost.Level)
+			}
+			if cost.Gold < 0 || cost.Cards < 0 {
+				return fmt.Errorf("%s level %d: costs must not be negative", kind, cost.Level)
+			}
+			if kind == UpgradeTower && cost.Cards != 0 {
+				return fmt.Errorf("tower level %d: towers do not use cards", cost.Level)
+			}
+		}
+	}
+	return nil
+}
+
+// NextCost returns the cost of raising something at currentLevel by one
+// level, or false once it is at the highest level listed
+func (c *UpgradeCatalogue) NextCost(kind UpgradeKind, currentLevel int) (UpgradeCost, bool) {
+	costs := c.TroopLevels
+	if kind == UpgradeTower {
+		costs = c.TowerLevels
+	}
+
+	index := currentLevel - 1
+	if index < 0 || index >= len(costs) {
+		return UpgradeCost{}, false
+	}
+	return costs[index], true
+}
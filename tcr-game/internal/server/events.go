@@ -0,0 +1,174 @@
+// internal/server/events.go - Pushes game events to WebSocket clients
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"tcr-game/internal/game"
+	"tcr-game/pkg/protocol"
+)
+
+// eventMessages build the protocol message each game event is pushed to
+// the game's clients as
+var eventMessages = map[game.EventType]func(event game.GameEventData) (interface{}, error){
+	game.EventPlayerJoined: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.PlayerJoinedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypePlayerJoined
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventGameStarted: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.GameStartedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeGameStarted
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventTurnChanged: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.TurnChangedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeTurnChanged
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventActionResult: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.ActionResultMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeActionResult
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventAttackMade: func(event game.GameEventData) (interface{}, error) {
+		return protocol.AttackMadeMessage{Type: protocol.MsgTypeAttackMade, Seq: event.Seq, Battle: event.Data}, nil
+	},
+	game.EventTowerDestroyed: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.TowerDestroyedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeTowerDestroyed
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventManaUpdated: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.ManaUpdatedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeManaUpdated
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventArenaTick: func(event game.GameEventData) (interface{}, error) {
+		return protocol.ArenaTickMessage{Type: protocol.MsgTypeArenaTick, Seq: event.Seq, Tick: event.Data}, nil
+	},
+	game.EventPhaseChanged: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.PhaseChangedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypePhaseChanged
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventDraftUpdated: func(event game.GameEventData) (interface{}, error) {
+		return protocol.DraftUpdateMessage{Type: protocol.MsgTypeDraftUpdate, Seq: event.Seq, Draft: event.Data}, nil
+	},
+	game.EventPlayerEliminated: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.PlayerEliminatedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypePlayerEliminated
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventPlayerDisconnected: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.PlayerDisconnectedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypePlayerDisconnected
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventPlayerReconnected: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.PlayerReconnectedMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypePlayerReconnected
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventGameEnded: func(event game.GameEventData) (interface{}, error) {
+		message := protocol.GameEndMessage{}
+		err := decodeEvent(event, &message)
+		message.Type = protocol.MsgTypeGameEnd
+		message.Seq = event.Seq
+		return message, err
+	},
+	game.EventMatchRewards: func(event game.GameEventData) (interface{}, error) {
+		return protocol.MatchRewardsMessage{Type: protocol.MsgTypeMatchRewards, Seq: event.Seq, Rewards: event.Data}, nil
+	},
+}
+
+// handleGameEvents subscribes the server to every game event the engine
+// publishes, so each state change reaches the game's WebSocket clients
+func (s *Server) handleGameEvents() {
+	for eventType := range eventMessages {
+		s.gameEngine.Events().Handle(eventType, func(event game.GameEventData) {
+			message, err := eventMessage(event)
+			if err != nil {
+				log.Printf("Failed to build %s message for game %s: %v", event.Type, event.GameID, err)
+				return
+			}
+			s.wsManager.BroadcastToGame(event.GameID, event.Seq, message)
+		})
+	}
+}
+
+// streamGame starts sending a game's events to a player's connection. A
+// client that has seen the game's events through since is sent the ones it
+// missed from the event history; one that is new, or further behind than
+// the history reaches, is sent a snapshot of the game's state as the
+// player sees it first.
+func (s *Server) streamGame(client *wsClient, gameID, playerID string, since int64) {
+	events := s.gameEngine.Events()
+
+	var snapshot map[string]interface{}
+	if _, kept := events.Since(gameID, since); !kept {
+		snapshot, since, _ = s.snapshot(gameID, playerID)
+	}
+
+	// Events published while the snapshot was read are sent after it, and
+	// the connection is added before any later ones are broadcast
+	s.wsManager.AddConnection(gameID, client, func() int64 {
+		if snapshot != nil {
+			s.wsManager.SendToConnection(client, protocol.GameStateMessage{
+				Type: protocol.MsgTypeGameState,
+				Seq:  since,
+				Data: snapshot,
+			})
+		}
+
+		missed, _ := events.Since(gameID, since)
+		for _, event := range missed {
+			if message, err := eventMessage(event); err == nil {
+				s.wsManager.SendToConnection(client, message)
+			}
+			since = event.Seq
+		}
+		return since
+	})
+}
+
+// eventMessage builds the protocol message a game event is sent as
+func eventMessage(event game.GameEventData) (interface{}, error) {
+	build, exists := eventMessages[event.Type]
+	if !exists {
+		return nil, fmt.Errorf("no message for event %s", event.Type)
+	}
+	return build(event)
+}
+
+// decodeEvent copies an event's data into the protocol message with the
+// same JSON fields
+func decodeEvent(event game.GameEventData, message interface{}) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, message)
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 	
 	"github.com/gorilla/mux"
 	"tcr-game/internal/auth"
 	"tcr-game/internal/game"
 	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+	"tcr-game/pkg/protocol"
 )
 
 func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
@@ -77,8 +80,9 @@ func (s *Server) handleCreateGame(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	var request struct {
-		Mode   string `json:"mode"`
-		GameID string `json:"game_id"`
+		Mode    string `json:"mode"`
+		GameID  string `json:"game_id"`
+		Players int    `json:"players"` // seats for modes that allow a choice, 0 for the mode's usual number
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -86,38 +90,40 @@ func (s *Server) handleCreateGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	var gameMode models.GameMode
-	switch request.Mode {
-	case "simple":
-		gameMode = models.SimpleMode
-	case "enhanced":
-		gameMode = models.EnhancedMode
-	default:
-		http.Error(w, "Invalid game mode", http.StatusBadRequest)
-		return
-	}
-	
-	game, err := s.gameEngine.CreateGame(request.GameID, gameMode)
+	state, err := s.createGame(player, request.GameID, request.Mode, request.Players)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	
-	// Join the game immediately
-	if err := s.gameEngine.JoinGame(request.GameID, player); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	
 	response := map[string]interface{}{
 		"success": true,
-		"game":    game,
+		"game":    state,
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// createGame creates a game, seats the player creating it and returns the
+// game's state. The model itself is never sent: it holds player passwords.
+func (s *Server) createGame(player *models.Player, gameID, mode string, players int) (map[string]interface{}, error) {
+	gameMode, ok := parseGameMode(mode)
+	if !ok {
+		return nil, fmt.Errorf("invalid game mode")
+	}
+	
+	if _, err := s.gameEngine.CreateGameWithPlayers(gameID, gameMode, players); err != nil {
+		return nil, err
+	}
+	
+	// Join the game immediately
+	if err := s.gameEngine.JoinGame(gameID, player); err != nil {
+		return nil, err
+	}
+	return s.gameEngine.GetGameStateFor(gameID, player.ID)
+}
+
 func (s *Server) handleJoinGame(w http.ResponseWriter, r *http.Request) {
 	player, err := s.validateToken(r)
 	if err != nil {
@@ -133,11 +139,11 @@ func (s *Server) handleJoinGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	gameObj, _ := s.gameEngine.GetGame(gameID)
+	state, _ := s.gameEngine.GetGameStateFor(gameID, player.ID)
 	
 	response := map[string]interface{}{
 		"success": true,
-		"game":    gameObj,
+		"game":    state,
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
@@ -145,7 +151,7 @@ func (s *Server) handleJoinGame(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleGetGameState(w http.ResponseWriter, r *http.Request) {
-	_, err := s.validateToken(r)
+	player, err := s.validateToken(r)
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -154,7 +160,7 @@ func (s *Server) handleGetGameState(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameID"]
 	
-	state, err := s.gameEngine.GetGameState(gameID)
+	state, err := s.gameEngine.GetGameStateFor(gameID, player.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -174,59 +180,247 @@ func (s *Server) handleGameAction(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	gameID := vars["gameID"]
 	
-	gameObj, err := s.gameEngine.GetGame(gameID)
-	if err != nil {
+	if _, err := s.gameEngine.GetGame(gameID); err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 	
-	var response interface{}
+	// The game's rule set decodes the action
+	var payload json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 	
-	if gameObj.Mode == models.SimpleMode {
-		var action game.TurnAction
-		if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
-			return
-		}
-		
-		result, err := s.gameEngine.ProcessSimpleAction(gameID, player.ID, action)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		response = result
-		
-		// Broadcast to WebSocket clients
-		s.wsManager.BroadcastToGame(gameID, map[string]interface{}{
-			"type":   "turn_result",
-			"result": result,
-		})
-	} else {
-		var action game.EnhancedAction
-		if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
-			return
-		}
-		action.Timestamp = time.Now()
-		
-		result, err := s.gameEngine.ProcessEnhancedAction(gameID, player.ID, action)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		response = result
-		
-		// Broadcast to WebSocket clients
-		s.wsManager.BroadcastToGame(gameID, map[string]interface{}{
-			"type":   "action_result",
-			"result": result,
-		})
+	// The result reaches WebSocket clients as an action_result event
+	result, err := s.gameEngine.ProcessAction(gameID, player.ID, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// parseGameMode accepts any mode with a registered rule set
+func parseGameMode(mode string) (models.GameMode, bool) {
+	gameMode := models.GameMode(mode)
+	return gameMode, game.IsRegisteredMode(gameMode)
+}
+
+func (s *Server) handleJoinQueue(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	var request struct {
+		Mode string `json:"mode"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	gameMode, ok := parseGameMode(request.Mode)
+	if !ok {
+		http.Error(w, "Invalid game mode", http.StatusBadRequest)
+		return
+	}
+	
+	status, err := s.matchmaker.Enqueue(player, gameMode, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (s *Server) handleGetQueueStatus(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.matchmaker.Status(player.ID, time.Now()))
+}
+
+func (s *Server) handleLeaveQueue(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	if err := s.matchmaker.Cancel(player.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	
+	response := map[string]interface{}{
+		"success": true,
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+func (s *Server) handleGetUpgrades(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	options, err := s.gameEngine.GetUpgradeOptions(player.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(options)
+}
+
+func (s *Server) handleUpgrade(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	var request game.UpgradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	result, err := s.gameEngine.UpgradePlayer(player.ID, request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (s *Server) handleGetDecks(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	decks, err := s.gameEngine.GetDecks(player.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decks)
+}
+
+func (s *Server) handleSaveDeck(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	slot, _ := strconv.Atoi(mux.Vars(r)["slot"])
+	
+	var deck models.Deck
+	if err := json.NewDecoder(r.Body).Decode(&deck); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	decks, err := s.gameEngine.SaveDeck(player.ID, slot, deck)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decks)
+}
+
+func (s *Server) handleSelectDeck(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	var request struct {
+		Slot int `json:"slot"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	decks, err := s.gameEngine.SelectDeck(player.ID, request.Slot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decks)
+}
+
+func (s *Server) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	mode := models.GameMode(query.Get("mode"))
+	sortBy := storage.LeaderboardSort(query.Get("sort"))
+	
+	leaderboard, err := s.storage.Leaderboard().Page(mode, sortBy, page, pageSize, player.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// notifyMatch tells both players of a new match which game to connect to
+func (s *Server) notifyMatch(match *game.Match) {
+	for i, player := range match.Players {
+		opponent := match.Players[1-i]
+		s.wsManager.SendToPlayer(player.ID, protocol.MatchFoundMessage{
+			Type:   protocol.MsgTypeMatchFound,
+			GameID: match.GameID,
+			Mode:   string(match.Mode),
+			Opponent: protocol.OpponentInfo{
+				ID:       opponent.ID,
+				Username: opponent.Username,
+				Level:    opponent.Level,
+				Rating:   opponent.RatingFor(match.Mode).Value,
+			},
+		})
+	}
+}
+
 func (s *Server) validateToken(r *http.Request) (*models.Player, error) {
 	token := r.Header.Get("Authorization")
 	if token == "" {
@@ -239,4 +433,95 @@ func (s *Server) validateToken(r *http.Request) (*models.Player, error) {
 	}
 	
 	return s.authService.ValidateToken(token)
-}
\ No newline at end of file
+}
+
+// authorizeAdmin answers requests from players not listed in the server's
+// admins with an error, and reports whether the request may go on
+func (s *Server) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	player, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	
+	for _, username := range s.config.Server.Admins {
+		if username == player.Username {
+			return true
+		}
+	}
+	http.Error(w, "Forbidden", http.StatusForbidden)
+	return false
+}
+
+func (s *Server) handleGetReplay(w http.ResponseWriter, r *http.Request) {
+	_, err := s.validateToken(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	
+	vars := mux.Vars(r)
+	gameID := vars["gameID"]
+	
+	replay, err := s.gameEngine.GetReplay(gameID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	
+	verifyError := ""
+	if err := s.gameEngine.VerifyReplay(replay); err != nil {
+		verifyError = err.Error()
+	}
+	
+	response := map[string]interface{}{
+		"replay":       replay,
+		"verified":     verifyError == "",
+		"verify_error": verifyError,
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) handleGetBalance(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.balanceState(s.gameEngine.Balance()))
+}
+
+// handleReloadBalance loads the balance files now instead of waiting for the watcher
+func (s *Server) handleReloadBalance(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	
+	balance, err := s.balance.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	s.gameEngine.SetBalance(balance)
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.balanceState(balance))
+}
+
+func (s *Server) balanceState(balance *game.Balance) map[string]interface{} {
+	games := make(map[int]int)
+	for _, gameObj := range s.gameEngine.GetActiveGames() {
+		games[gameObj.BalanceVersion]++
+	}
+	
+	return map[string]interface{}{
+		"version":         balance.Version,
+		"loaded_at":       balance.LoadedAt,
+		"files":           s.balance.Files(),
+		"troops":          balance.Troops.Len(),
+		"game":            balance.Game,
+		"games_by_version": games,
+	}
+}
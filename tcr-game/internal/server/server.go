@@ -18,32 +18,68 @@ type Server struct {
 	router      *mux.Router
 	httpServer  *http.Server
 	gameEngine  *game.GameEngine
+	storage     *storage.JSONStorage
 	authService *auth.AuthService
 	wsManager   *WebSocketManager
+	matchmaker  *game.Matchmaker
+	balance     *game.BalanceLoader
+	stopWatch   chan struct{}
+	stopMatches chan struct{}
 }
 
-func New(cfg *config.Config) *Server {
+func New(cfg *config.Config, configPath string) (*Server, error) {
 	// Initialize storage
+	gameStorage := storage.NewGameStorage(cfg.Database.GamesDir)
 	storage := storage.NewJSONStorage(
 		cfg.Database.PlayersDir,
 		cfg.Database.TroopsFile,
 		cfg.Database.TowersFile,
+		cfg.Database.UpgradesFile,
 	)
 	
+	// Index stored players for the leaderboards
+	if err := storage.LoadLeaderboard(); err != nil {
+		return nil, err
+	}
+	
+	// Load balance data
+	balanceLoader := game.NewBalanceLoader(configPath, storage)
+	balance, err := balanceLoader.Load()
+	if err != nil {
+		return nil, err
+	}
+	
 	// Initialize services
 	authService := auth.NewAuthService(storage)
-	gameEngine := game.NewGameEngine(cfg, storage)
-	wsManager := NewWebSocketManager()
+	gameEngine := game.NewGameEngine(cfg, storage, gameStorage, balance)
+	storage.Leaderboard().AddModes(gameEngine.Modes()...)
+	if err := cfg.Server.WebSocket.Validate(); err != nil {
+		return nil, err
+	}
+	wsManager := NewWebSocketManager(cfg.Server.WebSocket)
+	if cfg.Server.WebSocket.ResumeBuffer > 0 {
+		gameEngine.Events().SetHistorySize(cfg.Server.WebSocket.ResumeBuffer)
+	}
+	if cfg.Server.GameRetention > 0 {
+		gameEngine.SetGameRetention(time.Duration(cfg.Server.GameRetention) * time.Second)
+	}
+	matchmaker := game.NewMatchmaker(gameEngine, cfg.Matchmaking)
 	
 	s := &Server{
 		config:      cfg,
 		gameEngine:  gameEngine,
+		storage:     storage,
 		authService: authService,
 		wsManager:   wsManager,
+		matchmaker:  matchmaker,
+		balance:     balanceLoader,
 	}
 	
+	matchmaker.SetMatchHandler(s.notifyMatch)
+	s.handleGameEvents()
+	
 	s.setupRoutes()
-	return s
+	return s, nil
 }
 
 func (s *Server) setupRoutes() {
@@ -64,8 +100,29 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/api/games/{gameID}/join", s.handleJoinGame).Methods("POST")
 	s.router.HandleFunc("/api/games/{gameID}/state", s.handleGetGameState).Methods("GET")
 	s.router.HandleFunc("/api/games/{gameID}/action", s.handleGameAction).Methods("POST")
+	s.router.HandleFunc("/api/games/{gameID}/replay", s.handleGetReplay).Methods("GET")
+	
+	// Matchmaking routes
+	s.router.HandleFunc("/api/matchmaking/queue", s.handleJoinQueue).Methods("POST")
+	s.router.HandleFunc("/api/matchmaking/queue", s.handleGetQueueStatus).Methods("GET")
+	s.router.HandleFunc("/api/matchmaking/queue", s.handleLeaveQueue).Methods("DELETE")
+	
+	// Player routes
+	s.router.HandleFunc("/api/players/me/upgrades", s.handleGetUpgrades).Methods("GET")
+	s.router.HandleFunc("/api/players/me/upgrades", s.handleUpgrade).Methods("POST")
+	s.router.HandleFunc("/api/players/me/decks", s.handleGetDecks).Methods("GET")
+	s.router.HandleFunc("/api/players/me/decks/active", s.handleSelectDeck).Methods("PUT")
+	s.router.HandleFunc("/api/players/me/decks/{slot:[0-9]+}", s.handleSaveDeck).Methods("PUT")
 	
-	// WebSocket route
+	// Leaderboard routes
+	s.router.HandleFunc("/api/leaderboard", s.handleLeaderboard).Methods("GET")
+	
+	// Admin routes
+	s.router.HandleFunc("/api/admin/balance", s.handleGetBalance).Methods("GET")
+	s.router.HandleFunc("/api/admin/balance/reload", s.handleReloadBalance).Methods("POST")
+	
+	// WebSocket routes
+	s.router.HandleFunc("/ws/matchmaking", s.handleMatchmakingSocket)
 	s.router.HandleFunc("/ws/{gameID}", s.handleWebSocket)
 	
 	// Serve index.html at root
@@ -77,6 +134,11 @@ func (s *Server) setupRoutes() {
 	s.router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir("web/static/"))))
 }
 
+// Handler returns the server's routes, for serving them without Start
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
 func (s *Server) Start(port string) error {
 	s.httpServer = &http.Server{
 		Addr:         ":" + port,
@@ -85,9 +147,26 @@ func (s *Server) Start(port string) error {
 		WriteTimeout: time.Duration(s.config.Server.WriteTimeout) * time.Second,
 	}
 	
+	if s.config.Server.BalancePoll > 0 {
+		interval := time.Duration(s.config.Server.BalancePoll) * time.Millisecond
+		s.stopWatch = s.balance.Watch(interval, s.gameEngine.SetBalance)
+	}
+	
+	s.stopMatches = s.matchmaker.Start()
+	
 	return s.httpServer.ListenAndServe()
 }
 
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	if s.stopWatch != nil {
+		close(s.stopWatch)
+	}
+	if s.stopMatches != nil {
+		close(s.stopMatches)
+	}
+	err := s.httpServer.Shutdown(ctx)
+	
+	// Results of games that just ended are still saved
+	s.gameEngine.WaitForResults()
+	return err
 }
\ No newline at end of file
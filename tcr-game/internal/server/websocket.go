@@ -2,28 +2,42 @@
 package server
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 	
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
+	"tcr-game/config"
+	"tcr-game/internal/models"
+	"tcr-game/pkg/protocol"
 )
 
 type WebSocketManager struct {
-	connections map[string]map[*websocket.Conn]bool // gameID -> connections
+	connections map[string]map[*wsClient]bool // gameID -> connections
+	players     map[string]map[*wsClient]bool // playerID -> connections
+	settings    wsSettings
 	mutex       sync.RWMutex
 	upgrader    websocket.Upgrader
 }
 
+// WSMessage is the envelope of a message sent by a client: its type, the
+// request ID to answer it under and, for draft messages, the action.
+// Messages to clients are the typed messages of the protocol package.
 type WSMessage struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	Type      string      `json:"type"`
+	RequestID string      `json:"request_id,omitempty"`
+	Data      interface{} `json:"data"`
 }
 
-func NewWebSocketManager() *WebSocketManager {
+func NewWebSocketManager(cfg config.WebSocketConfig) *WebSocketManager {
 	return &WebSocketManager{
-		connections: make(map[string]map[*websocket.Conn]bool),
+		connections: make(map[string]map[*wsClient]bool),
+		players:     make(map[string]map[*wsClient]bool),
+		settings:    newWSSettings(cfg),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins in development
@@ -49,101 +63,233 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Only a game's players may connect to it, and anyone may connect to
+	// a game still waiting for players to join it
+	game, err := s.gameEngine.GetGame(gameID)
+	if err != nil {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+	if game.TeamOf(player.ID) < 0 && game.State != models.Waiting {
+		http.Error(w, "Not a player in this game", http.StatusForbidden)
+		return
+	}
+	
 	// Upgrade connection
 	conn, err := s.wsManager.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
-	defer conn.Close()
+	client := s.wsManager.connect(conn)
+	defer client.close()
+	
+	// Add connection to game, catching up from the last event the client
+	// saw if it is reconnecting
+	s.streamGame(client, gameID, player.ID, parseSince(r))
+	defer s.wsManager.RemoveConnection(gameID, client)
+	s.wsManager.AddPlayerConnection(player.ID, client)
+	defer s.wsManager.RemovePlayerConnection(player.ID, client)
 	
-	// Add connection to game
-	s.wsManager.AddConnection(gameID, conn)
-	defer s.wsManager.RemoveConnection(gameID, conn)
+	// The game knows the player is here until their last connection closes
+	s.gameEngine.PlayerConnected(gameID, player.ID)
+	defer s.gameEngine.PlayerDisconnected(gameID, player.ID)
 	
-	// Send initial game state
-	state, err := s.gameEngine.GetGameState(gameID)
+	// Handle incoming messages
+	for {
+		var raw json.RawMessage
+		if err := conn.ReadJSON(&raw); err != nil {
+			log.Printf("WebSocket read error: %v", err)
+			break
+		}
+		
+		var msg WSMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			s.wsManager.SendToConnection(client, protocol.ErrorMessage{
+				Type:  protocol.MsgTypeError,
+				Error: "invalid message",
+			})
+			continue
+		}
+		
+		// Handle different message types; the rest are game requests
+		switch msg.Type {
+		case protocol.MsgTypePing:
+			s.wsManager.SendToConnection(client, protocol.PongMessage{Type: protocol.MsgTypePong})
+		case protocol.MsgTypeGetState:
+			s.sendGameState(client, gameID, player.ID)
+		default:
+			s.handleGameRequest(client, gameID, player, msg, raw)
+		}
+	}
+}
+
+// parseSince reads the seq of the last event a reconnecting client saw,
+// or -1 for a client connecting afresh
+func parseSince(r *http.Request) int64 {
+	since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+	if err != nil || since < 0 {
+		return -1
+	}
+	return since
+}
+
+// sendGameState sends a game's current state to one player's connection
+func (s *Server) sendGameState(client *wsClient, gameID, playerID string) {
+	state, seq, err := s.snapshot(gameID, playerID)
 	if err == nil {
-		s.wsManager.SendToConnection(conn, WSMessage{
-			Type: "game_state",
+		s.wsManager.SendToConnection(client, protocol.GameStateMessage{
+			Type: protocol.MsgTypeGameState,
+			Seq:  seq,
 			Data: state,
 		})
 	}
+}
+
+// snapshotAttempts is how many times a game's state is read while events
+// keep being published during the read
+const snapshotAttempts = 3
+
+// snapshot reads a game's state as a player sees it, with the seq of the
+// last event it reflects. A read that events were published during is
+// retried, since the state may already show them; if the game stays that
+// busy, the seq from before the last read is kept, so events are repeated
+// rather than missed.
+func (s *Server) snapshot(gameID, playerID string) (map[string]interface{}, int64, error) {
+	events := s.gameEngine.Events()
+	for attempt := 1; ; attempt++ {
+		seq := events.LastSeq(gameID)
+		state, err := s.gameEngine.GetGameStateFor(gameID, playerID)
+		if err != nil {
+			return nil, seq, err
+		}
+		if events.LastSeq(gameID) == seq || attempt == snapshotAttempts {
+			return state, seq, nil
+		}
+	}
+}
+
+// handleMatchmakingSocket keeps a connection open while a player waits in
+// the matchmaking queue so they can be told when a match is found
+func (s *Server) handleMatchmakingSocket(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "Missing token", http.StatusUnauthorized)
+		return
+	}
 	
-	// Send player joined event
-	s.wsManager.BroadcastToGame(gameID, WSMessage{
-		Type: "player_joined",
-		Data: map[string]interface{}{
-			"player_id": player.ID,
-			"username":  player.Username,
-		},
-	})
+	player, err := s.authService.ValidateToken(token)
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+	
+	conn, err := s.wsManager.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	client := s.wsManager.connect(conn)
+	defer client.close()
+	
+	s.wsManager.AddPlayerConnection(player.ID, client)
+	defer s.wsManager.RemovePlayerConnection(player.ID, client)
+	
+	s.sendQueueStatus(client, player.ID)
 	
-	// Handle incoming messages
 	for {
 		var msg WSMessage
 		if err := conn.ReadJSON(&msg); err != nil {
-			log.Printf("WebSocket read error: %v", err)
 			break
 		}
 		
-		// Handle different message types
 		switch msg.Type {
-		case "ping":
-			s.wsManager.SendToConnection(conn, WSMessage{Type: "pong", Data: nil})
-		case "get_state":
-			state, err := s.gameEngine.GetGameState(gameID)
-			if err == nil {
-				s.wsManager.SendToConnection(conn, WSMessage{
-					Type: "game_state",
-					Data: state,
-				})
-			}
+		case protocol.MsgTypePing:
+			s.wsManager.SendToConnection(client, protocol.PongMessage{Type: protocol.MsgTypePong})
+		case protocol.MsgTypeGetStatus:
+			s.sendQueueStatus(client, player.ID)
 		}
 	}
 }
 
-func (wsm *WebSocketManager) AddConnection(gameID string, conn *websocket.Conn) {
+// sendQueueStatus sends a player's place in the matchmaking queue to one connection
+func (s *Server) sendQueueStatus(client *wsClient, playerID string) {
+	s.wsManager.SendToConnection(client, protocol.QueueStatusMessage{
+		Type:   protocol.MsgTypeQueueStatus,
+		Status: s.matchmaker.Status(playerID, time.Now()),
+	})
+}
+
+// AddConnection adds a connection to a game. CatchUp sends it what it
+// missed before any new broadcast and returns the seq of the last event
+// sent; events broadcast after the connection is added but already sent
+// by catchUp are skipped.
+func (wsm *WebSocketManager) AddConnection(gameID string, client *wsClient, catchUp func() int64) {
 	wsm.mutex.Lock()
 	defer wsm.mutex.Unlock()
 	
+	client.since = catchUp()
+	
 	if wsm.connections[gameID] == nil {
-		wsm.connections[gameID] = make(map[*websocket.Conn]bool)
+		wsm.connections[gameID] = make(map[*wsClient]bool)
 	}
-	wsm.connections[gameID][conn] = true
+	wsm.connections[gameID][client] = true
 }
 
-func (wsm *WebSocketManager) RemoveConnection(gameID string, conn *websocket.Conn) {
+func (wsm *WebSocketManager) RemoveConnection(gameID string, client *wsClient) {
 	wsm.mutex.Lock()
 	defer wsm.mutex.Unlock()
 	
 	if connections, exists := wsm.connections[gameID]; exists {
-		delete(connections, conn)
+		delete(connections, client)
 		if len(connections) == 0 {
 			delete(wsm.connections, gameID)
 		}
 	}
 }
 
-func (wsm *WebSocketManager) BroadcastToGame(gameID string, message interface{}) {
-	wsm.mutex.RLock()
-	connections, exists := wsm.connections[gameID]
-	wsm.mutex.RUnlock()
+func (wsm *WebSocketManager) AddPlayerConnection(playerID string, client *wsClient) {
+	wsm.mutex.Lock()
+	defer wsm.mutex.Unlock()
 	
-	if !exists {
-		return
+	if wsm.players[playerID] == nil {
+		wsm.players[playerID] = make(map[*wsClient]bool)
 	}
+	wsm.players[playerID][client] = true
+}
+
+func (wsm *WebSocketManager) RemovePlayerConnection(playerID string, client *wsClient) {
+	wsm.mutex.Lock()
+	defer wsm.mutex.Unlock()
 	
+	if connections, exists := wsm.players[playerID]; exists {
+		delete(connections, client)
+		if len(connections) == 0 {
+			delete(wsm.players, playerID)
+		}
+	}
+}
+
+// SendToPlayer sends a message to every open connection of a player
+func (wsm *WebSocketManager) SendToPlayer(playerID string, message interface{}) {
 	wsm.mutex.RLock()
-	for conn := range connections {
-		go wsm.SendToConnection(conn, message)
+	defer wsm.mutex.RUnlock()
+	
+	for client := range wsm.players[playerID] {
+		wsm.SendToConnection(client, message)
 	}
-	wsm.mutex.RUnlock()
 }
 
-func (wsm *WebSocketManager) SendToConnection(conn *websocket.Conn, message interface{}) {
-	if err := conn.WriteJSON(message); err != nil {
-		log.Printf("WebSocket write error: %v", err)
-		conn.Close()
+// BroadcastToGame queues the message of a game's event for every
+// connection of the game that has not been sent it yet. Each connection
+// receives the game's messages in the order they were broadcast.
+func (wsm *WebSocketManager) BroadcastToGame(gameID string, seq int64, message interface{}) {
+	wsm.mutex.RLock()
+	defer wsm.mutex.RUnlock()
+	
+	for client := range wsm.connections[gameID] {
+		if seq > client.since {
+			wsm.SendToConnection(client, message)
+		}
 	}
-}
\ No newline at end of file
+}
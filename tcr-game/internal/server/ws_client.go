@@ -0,0 +1,150 @@
+// internal/server/ws_client.go - Per-connection WebSocket write pump
+package server
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"tcr-game/config"
+)
+
+// Defaults for WebSocket settings left at zero
+const (
+	defaultQueueSize    = 256
+	defaultWriteTimeout = 10 * time.Second
+	defaultPingInterval = 30 * time.Second
+	defaultPongTimeout  = 60 * time.Second
+)
+
+// wsSettings are the WebSocket settings with defaults filled in
+type wsSettings struct {
+	queueSize    int
+	writeTimeout time.Duration
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	slowConsumer string
+}
+
+func newWSSettings(cfg config.WebSocketConfig) wsSettings {
+	settings := wsSettings{
+		queueSize:    cfg.QueueSize,
+		writeTimeout: time.Duration(cfg.WriteTimeout) * time.Millisecond,
+		pingInterval: time.Duration(cfg.PingInterval) * time.Millisecond,
+		pongTimeout:  time.Duration(cfg.PongTimeout) * time.Millisecond,
+		slowConsumer: cfg.SlowConsumer,
+	}
+	if settings.queueSize == 0 {
+		settings.queueSize = defaultQueueSize
+	}
+	if settings.writeTimeout == 0 {
+		settings.writeTimeout = defaultWriteTimeout
+	}
+	if settings.pongTimeout == 0 {
+		settings.pongTimeout = defaultPongTimeout
+	}
+	if settings.pingInterval == 0 {
+		settings.pingInterval = defaultPingInterval
+	}
+	if settings.pingInterval >= settings.pongTimeout {
+		settings.pingInterval = settings.pongTimeout * 9 / 10
+	}
+	if settings.slowConsumer == "" {
+		settings.slowConsumer = config.SlowConsumerDisconnect
+	}
+	return settings
+}
+
+// wsClient is one WebSocket connection. Messages to it are queued and
+// written in the order they were sent by its write pump, the only
+// goroutine that writes to the connection. Since is the seq of the last
+// game event it was sent when it caught up.
+type wsClient struct {
+	conn      *websocket.Conn
+	send      chan interface{}
+	done      chan struct{}
+	closeOnce sync.Once
+	since     int64
+}
+
+// connect starts the write pump of a new connection and has its reads
+// time out unless the client answers the pump's pings
+func (wsm *WebSocketManager) connect(conn *websocket.Conn) *wsClient {
+	client := &wsClient{
+		conn: conn,
+		send: make(chan interface{}, wsm.settings.queueSize),
+		done: make(chan struct{}),
+	}
+
+	conn.SetReadDeadline(time.Now().Add(wsm.settings.pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsm.settings.pongTimeout))
+	})
+
+	go wsm.writePump(client)
+	return client
+}
+
+// writePump writes the client's queued messages and pings until the client
+// is closed or a write fails
+func (wsm *WebSocketManager) writePump(client *wsClient) {
+	ticker := time.NewTicker(wsm.settings.pingInterval)
+	defer ticker.Stop()
+	defer client.conn.Close()
+
+	for {
+		select {
+		case <-client.done:
+			client.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(wsm.settings.writeTimeout),
+			)
+			return
+		case message := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(wsm.settings.writeTimeout))
+			if err := client.conn.WriteJSON(message); err != nil {
+				log.Printf("WebSocket write error: %v", err)
+				client.close()
+				return
+			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(wsm.settings.writeTimeout))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				client.close()
+				return
+			}
+		}
+	}
+}
+
+// SendToConnection queues a message for a client without waiting for it
+// to be written. A client whose queue is full is a slow consumer: the
+// message is dropped or the client disconnected, as configured.
+func (wsm *WebSocketManager) SendToConnection(client *wsClient, message interface{}) {
+	select {
+	case <-client.done:
+		return
+	default:
+	}
+
+	select {
+	case client.send <- message:
+	default:
+		if wsm.settings.slowConsumer == config.SlowConsumerDrop {
+			log.Printf("WebSocket client is not keeping up, dropping message")
+			return
+		}
+		log.Printf("WebSocket client is not keeping up, disconnecting")
+		client.close()
+	}
+}
+
+// close stops the write pump, which closes the connection. Closing twice
+// does nothing.
+func (client *wsClient) close() {
+	client.closeOnce.Do(func() {
+		close(client.done)
+	})
+}
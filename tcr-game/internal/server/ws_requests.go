@@ -0,0 +1,111 @@
+// internal/server/ws_requests.go - Game requests sent over a game's WebSocket
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/pkg/protocol"
+)
+
+// handleGameRequest plays a join or action request sent over a game's
+// socket. The sender gets an ack carrying the result, or an error,
+// under the request's ID; the changes it makes reach every client of the
+// game as events.
+func (s *Server) handleGameRequest(client *wsClient, gameID string, player *models.Player, msg WSMessage, raw json.RawMessage) {
+	result, err := s.playRequest(gameID, player, msg, raw)
+	if err != nil {
+		s.wsManager.SendToConnection(client, protocol.ErrorMessage{
+			Type:      protocol.MsgTypeError,
+			RequestID: msg.RequestID,
+			Error:     err.Error(),
+		})
+		return
+	}
+
+	s.wsManager.SendToConnection(client, protocol.AckMessage{
+		Type:      protocol.MsgTypeAck,
+		RequestID: msg.RequestID,
+		Result:    result,
+	})
+}
+
+// playRequest decodes a request into its protocol message and plays it in
+// the socket's game. Actions the rules reject are acked with the rejected
+// result, as the HTTP endpoint answers them.
+func (s *Server) playRequest(gameID string, player *models.Player, msg WSMessage, raw json.RawMessage) (interface{}, error) {
+	switch msg.Type {
+	case protocol.MsgTypeJoinGame:
+		var request protocol.JoinGameMessage
+		if err := decodeRequest(raw, &request, gameID, &request.GameID); err != nil {
+			return nil, err
+		}
+		if err := s.gameEngine.JoinGame(gameID, player); err != nil {
+			return nil, err
+		}
+		// The socket was opened before the player had a seat, so the
+		// game starts tracking their presence now
+		s.gameEngine.PlayerConnected(gameID, player.ID)
+		return s.gameEngine.GetGameStateFor(gameID, player.ID)
+
+	case protocol.MsgTypeSimpleAction:
+		var request protocol.SimpleActionMessage
+		if err := decodeRequest(raw, &request, gameID, nil); err != nil {
+			return nil, err
+		}
+		return s.playAction(gameID, player.ID, game.TurnAction{
+			Type:         protocol.ActionTypeAttack,
+			TroopID:      request.TroopID,
+			TargetPlayer: request.TargetPlayer,
+			TargetTower:  request.TargetTower,
+		})
+
+	case protocol.MsgTypeEnhancedAction:
+		var request protocol.EnhancedActionMessage
+		if err := decodeRequest(raw, &request, gameID, nil); err != nil {
+			return nil, err
+		}
+		return s.playAction(gameID, player.ID, game.EnhancedAction{
+			Type:         protocol.ActionTypeSpawnTroop,
+			TroopID:      request.TroopID,
+			TargetPlayer: request.TargetPlayer,
+			TargetTower:  request.TargetTower,
+		})
+
+	case protocol.MsgTypeAction:
+		var request protocol.ActionMessage
+		if err := decodeRequest(raw, &request, gameID, nil); err != nil {
+			return nil, err
+		}
+		return s.gameEngine.ProcessAction(gameID, player.ID, request.Action)
+
+	case protocol.MsgTypeDraft:
+		// Draft picks and bans carry the action under data
+		return s.playAction(gameID, player.ID, msg.Data)
+	}
+
+	return nil, fmt.Errorf("unknown message type: %s", msg.Type)
+}
+
+// playAction sends an action to the game's rule set as a client would
+func (s *Server) playAction(gameID, playerID string, action interface{}) (game.ActionResult, error) {
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action")
+	}
+	return s.gameEngine.ProcessAction(gameID, playerID, payload)
+}
+
+// decodeRequest decodes a request's message. A game ID in the message, if
+// any, must name the game the socket was opened for.
+func decodeRequest(raw json.RawMessage, message interface{}, gameID string, requestGameID *string) error {
+	if err := json.Unmarshal(raw, message); err != nil {
+		return fmt.Errorf("invalid message")
+	}
+	if requestGameID != nil && *requestGameID != "" && *requestGameID != gameID {
+		return fmt.Errorf("socket is for game %s, not %s", gameID, *requestGameID)
+	}
+	return nil
+}
@@ -61,3 +61,43 @@ func (gs *GameStorage) DeleteGame(gameID string) error {
 	filename := filepath.Join(gs.gamesDir, fmt.Sprintf("%s.json", gameID))
 	return os.Remove(filename)
 }
+
+func (gs *GameStorage) replayFile(gameID string) string {
+	return filepath.Join(gs.gamesDir, "replays", fmt.Sprintf("%s.json", gameID))
+}
+
+func (gs *GameStorage) SaveReplay(replay *models.Replay) error {
+	filename := gs.replayFile(replay.GameID)
+	
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	
+	data, err := json.MarshalIndent(replay, "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (gs *GameStorage) LoadReplay(gameID string) (*models.Replay, error) {
+	filename := gs.replayFile(gameID)
+	
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, fmt.Errorf("replay not found: %s", gameID)
+	}
+	
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	
+	var replay models.Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	
+	return &replay, nil
+}
@@ -5,26 +5,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	
 	"tcr-game/internal/models"
+	"tcr-game/pkg/protocol"
 )
 
 type JSONStorage struct {
 	playersDir string
 	troopsFile string
 	towersFile string
+	upgradesFile string
+	leaderboard *Leaderboard
 }
 
-func NewJSONStorage(playersDir, troopsFile, towersFile string) *JSONStorage {
+func NewJSONStorage(playersDir, troopsFile, towersFile, upgradesFile string) *JSONStorage {
 	return &JSONStorage{
 		playersDir: playersDir,
 		troopsFile: troopsFile,
 		towersFile: towersFile,
+		upgradesFile: upgradesFile,
+		leaderboard: NewLeaderboard(),
 	}
 }
 
+func (js *JSONStorage) TroopsFile() string {
+	return js.troopsFile
+}
+
+func (js *JSONStorage) TowersFile() string {
+	return js.towersFile
+}
+
+func (js *JSONStorage) UpgradesFile() string {
+	return js.upgradesFile
+}
+
 func (js *JSONStorage) LoadPlayer(id string) (*models.Player, error) {
 	filename := filepath.Join(js.playersDir, fmt.Sprintf("%s.json", id))
 	
@@ -46,62 +65,311 @@ func (js *JSONStorage) LoadPlayer(id string) (*models.Player, error) {
 }
 
 func (js *JSONStorage) SavePlayer(player *models.Player) error {
+	return js.SavePlayers(player)
+}
+
+// SavePlayers writes every player to a temporary file first and only then
+// renames them into place, so a failed write leaves all records unchanged.
+// If a rename fails, the records already replaced are put back from their
+// previous contents.
+func (js *JSONStorage) SavePlayers(players ...*models.Player) error {
 	// Ensure directory exists
 	if err := os.MkdirAll(js.playersDir, 0755); err != nil {
 		return err
 	}
 	
-	filename := filepath.Join(js.playersDir, fmt.Sprintf("%s.json", player.ID))
+	tempFiles := make([]string, 0, len(players))
+	defer func() {
+		for _, tempFile := range tempFiles {
+			os.Remove(tempFile)
+		}
+	}()
 	
-	data, err := json.MarshalIndent(player, "", "  ")
+	for _, player := range players {
+		data, err := json.MarshalIndent(player, "", "  ")
+		if err != nil {
+			return err
+		}
+		
+		tempFile := filepath.Join(js.playersDir, fmt.Sprintf("%s.json.tmp", player.ID))
+		if err := ioutil.WriteFile(tempFile, data, 0644); err != nil {
+			return err
+		}
+		tempFiles = append(tempFiles, tempFile)
+	}
+	
+	filenames := make([]string, len(players))
+	previous := make([][]byte, len(players)) // nil for players not stored yet
+	for i, player := range players {
+		filenames[i] = filepath.Join(js.playersDir, fmt.Sprintf("%s.json", player.ID))
+		data, err := ioutil.ReadFile(filenames[i])
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		previous[i] = data
+	}
+	
+	for i := range players {
+		if err := os.Rename(tempFiles[i], filenames[i]); err != nil {
+			js.restorePlayers(filenames[:i], previous[:i])
+			return err
+		}
+	}
+	
+	for _, player := range players {
+		js.leaderboard.Update(player)
+	}
+	return nil
+}
+
+// restorePlayers puts back the previous contents of player files replaced
+// by a save that failed part way, removing those that did not exist before
+func (js *JSONStorage) restorePlayers(filenames []string, previous [][]byte) {
+	for i, filename := range filenames {
+		if previous[i] == nil {
+			os.Remove(filename)
+			continue
+		}
+		tempFile := filename + ".tmp"
+		if err := ioutil.WriteFile(tempFile, previous[i], 0644); err == nil {
+			os.Rename(tempFile, filename)
+		}
+	}
+}
+
+// Leaderboard returns the index kept up to date by SavePlayer
+func (js *JSONStorage) Leaderboard() *Leaderboard {
+	return js.leaderboard
+}
+
+// LoadLeaderboard indexes every stored player. It is called once at
+// startup; afterwards SavePlayer keeps the index current. Records that
+// cannot be read are logged and left out rather than stopping the server.
+func (js *JSONStorage) LoadLeaderboard() error {
+	files, err := ioutil.ReadDir(js.playersDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 	
-	return ioutil.WriteFile(filename, data, 0644)
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		
+		player, err := js.LoadPlayer(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			log.Printf("Skipping player record %s: %v", file.Name(), err)
+			continue
+		}
+		js.leaderboard.Update(player)
+	}
+	
+	return nil
 }
 
-func (js *JSONStorage) LoadTroops() ([]*models.Troop, error) {
+// LoadTroops reads and validates the troop catalogue. Every problem is
+// reported as a SchemaError naming the entry index and field.
+func (js *JSONStorage) LoadTroops() (*models.TroopCatalogue, error) {
 	data, err := ioutil.ReadFile(js.troopsFile)
 	if err != nil {
 		return nil, err
 	}
 	
-	var troopSpecs []map[string]interface{}
-	if err := json.Unmarshal(data, &troopSpecs); err != nil {
-		return nil, err
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: expected a list of troops: %v", js.troopsFile, err)
 	}
 	
-	troops := make([]*models.Troop, 0, len(troopSpecs))
-	for _, spec := range troopSpecs {
-		troop := &models.Troop{
-			ID:          spec["id"].(string),
-			Name:        spec["name"].(string),
-			HP:          int(spec["hp"].(float64)),
-			MaxHP:       int(spec["hp"].(float64)),
-			Attack:      int(spec["attack"].(float64)),
-			Defense:     int(spec["defense"].(float64)),
-			CritChance:  spec["crit_chance"].(float64),
-			ManaCost:    int(spec["mana_cost"].(float64)),
-			Description: spec["description"].(string),
-			Level:       1,
+	schemaErrors := &SchemaErrors{File: js.troopsFile}
+	troops := make([]*models.Troop, 0, len(entries))
+	firstIndex := make(map[string]int)
+	
+	for i, entry := range entries {
+		troop, valid := decodeTroop(i, entry, schemaErrors)
+		if troop == nil || troop.ID == "" {
+			continue
+		}
+		
+		// Duplicates are checked even for invalid entries so every
+		// definition of an ID is reported
+		if first, exists := firstIndex[troop.ID]; exists {
+			schemaErrors.add(i, "id", "duplicate id %q, first defined at entry %d", troop.ID, first)
+			continue
+		}
+		firstIndex[troop.ID] = i
+		if valid {
+			troops = append(troops, troop)
 		}
-		troops = append(troops, troop)
 	}
 	
-	return troops, nil
+	if len(entries) == 0 {
+		schemaErrors.add(0, "", "no troops defined")
+	}
+	if len(schemaErrors.Errors) > 0 {
+		return nil, schemaErrors
+	}
+	
+	return models.NewTroopCatalogue(troops), nil
+}
+
+// troopField maps a troops.json key onto a Troop field
+type troopField struct {
+	name     string
+	required bool
+	target   interface{}
 }
 
-func (js *JSONStorage) LoadTowers() (map[string]interface{}, error) {
+// decodeTroop decodes one troops.json entry field by field so a bad value
+// is reported against its own field. Optional fields keep their defaults.
+// It reports whether the entry passed validation.
+func decodeTroop(index int, entry json.RawMessage, schemaErrors *SchemaErrors) (*models.Troop, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(entry, &raw); err != nil {
+		schemaErrors.add(index, "", "entry must be an object")
+		return nil, false
+	}
+	
+	troop := models.NewTroop("", "", 0, 0, 0, 0, 0, "")
+	fields := []troopField{
+		{"id", true, &troop.ID},
+		{"name", true, &troop.Name},
+		{"hp", true, &troop.HP},
+		{"attack", true, &troop.Attack},
+		{"defense", true, &troop.Defense},
+		{"crit_chance", true, &troop.CritChance},
+		{"mana_cost", true, &troop.ManaCost},
+		{"description", false, &troop.Description},
+		{"speed", false, &troop.Speed},
+		{"unlock_level", false, &troop.UnlockLevel},
+		{"growth", false, &troop.Growth},
+	}
+	
+	errorCount := len(schemaErrors.Errors)
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.name] = true
+		value, exists := raw[field.name]
+		if !exists {
+			if field.required {
+				schemaErrors.add(index, field.name, "missing required field")
+			}
+			continue
+		}
+		if err := json.Unmarshal(value, field.target); err != nil {
+			schemaErrors.add(index, field.name, "expected %s, got %s", expectedType(field.target), string(value))
+		}
+	}
+	
+	for name := range raw {
+		if !known[name] {
+			schemaErrors.add(index, name, "unknown field")
+		}
+	}
+	
+	if len(schemaErrors.Errors) > errorCount {
+		return troop, false
+	}
+	
+	// Range checks
+	if troop.ID == "" {
+		schemaErrors.add(index, "id", "must not be empty")
+	}
+	if troop.HP <= 0 {
+		schemaErrors.add(index, "hp", "must be positive, got %d", troop.HP)
+	}
+	if troop.Attack < 0 {
+		schemaErrors.add(index, "attack", "must not be negative, got %d", troop.Attack)
+	}
+	if troop.Defense < 0 {
+		schemaErrors.add(index, "defense", "must not be negative, got %d", troop.Defense)
+	}
+	if troop.CritChance < 0 || troop.CritChance > 1 {
+		schemaErrors.add(index, "crit_chance", "must be between 0 and 1, got %v", troop.CritChance)
+	}
+	if troop.ManaCost < 0 || troop.ManaCost > protocol.DefaultManaMax {
+		schemaErrors.add(index, "mana_cost", "must be between 0 and %d, got %d", protocol.DefaultManaMax, troop.ManaCost)
+	}
+	if troop.Speed <= 0 {
+		schemaErrors.add(index, "speed", "must be positive, got %d", troop.Speed)
+	}
+	if troop.UnlockLevel < 1 {
+		schemaErrors.add(index, "unlock_level", "must be at least 1, got %d", troop.UnlockLevel)
+	}
+	if !troop.Growth.Valid() {
+		schemaErrors.add(index, "growth", "must not be negative, got %+v", troop.Growth)
+	}
+	
+	if len(schemaErrors.Errors) > errorCount {
+		return troop, false
+	}
+	
+	troop.MaxHP = troop.HP
+	troop.Base = models.Stats{HP: troop.HP, Attack: troop.Attack, Defense: troop.Defense}
+	return troop, true
+}
+
+func expectedType(target interface{}) string {
+	switch target.(type) {
+	case *string:
+		return "a string"
+	case *int:
+		return "an integer"
+	case *float64:
+		return "a number"
+	case *models.StatGrowth:
+		return "an object of hp, attack and defense growth"
+	default:
+		return "a valid value"
+	}
+}
+
+// LoadTowers reads and validates the tower catalogue. Files without a
+// layout get the default two guards and a king.
+func (js *JSONStorage) LoadTowers() (*models.TowerCatalogue, error) {
 	data, err := ioutil.ReadFile(js.towersFile)
 	if err != nil {
 		return nil, err
 	}
 	
-	var towers map[string]interface{}
-	if err := json.Unmarshal(data, &towers); err != nil {
+	var catalogue models.TowerCatalogue
+	if err := json.Unmarshal(data, &catalogue); err != nil {
+		return nil, fmt.Errorf("%s: %v", js.towersFile, err)
+	}
+	
+	if len(catalogue.Layout) == 0 {
+		catalogue.Layout = models.DefaultTowerCatalogue().Layout
+	}
+	
+	if err := catalogue.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", js.towersFile, err)
+	}
+	
+	return &catalogue, nil
+}
+
+// LoadUpgrades reads the upgrade costs and match currency rewards. Without
+// an upgrades file the default costs are used.
+func (js *JSONStorage) LoadUpgrades() (*models.UpgradeCatalogue, error) {
+	if js.upgradesFile == "" {
+		return models.DefaultUpgradeCatalogue(), nil
+	}
+	
+	data, err := ioutil.ReadFile(js.upgradesFile)
+	if err != nil {
 		return nil, err
 	}
 	
-	return towers, nil
-}
\ No newline at end of file
+	var catalogue models.UpgradeCatalogue
+	if err := json.Unmarshal(data, &catalogue); err != nil {
+		return nil, fmt.Errorf("%s: %v", js.upgradesFile, err)
+	}
+	
+	if err := catalogue.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", js.upgradesFile, err)
+	}
+	
+	return &catalogue, nil
+}
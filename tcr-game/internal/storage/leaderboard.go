@@ -0,0 +1,280 @@
+// internal/storage/leaderboard.go - In-memory leaderboard index
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"tcr-game/internal/models"
+)
+
+type LeaderboardSort string
+
+const (
+	SortByRating LeaderboardSort = "rating"
+	SortByWins   LeaderboardSort = "wins"
+	SortByLevel  LeaderboardSort = "level"
+)
+
+const (
+	DefaultLeaderboardPageSize = 20
+	MaxLeaderboardPageSize     = 100
+)
+
+// LeaderboardEntry is one player's row. Players with equal scores share a
+// rank, and the next rank skips accordingly (1, 2, 2, 4).
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank"`
+	PlayerID    string `json:"player_id"`
+	Username    string `json:"username"`
+	Level       int    `json:"level"`
+	Experience  int    `json:"experience"`
+	GamesPlayed int    `json:"games_played"`
+	GamesWon    int    `json:"games_won"`
+	Rating      int    `json:"rating"`
+}
+
+type LeaderboardPage struct {
+	Mode         models.GameMode    `json:"mode"`
+	Sort         LeaderboardSort    `json:"sort"`
+	Page         int                `json:"page"`
+	PageSize     int                `json:"page_size"`
+	TotalPlayers int                `json:"total_players"`
+	TotalPages   int                `json:"total_pages"`
+	Entries      []LeaderboardEntry `json:"entries"`
+	Own          *LeaderboardEntry  `json:"own,omitempty"` // the requesting player's row, wherever it is
+}
+
+// leaderboardRecord holds the ranked fields of one player
+type leaderboardRecord struct {
+	id          string
+	username    string
+	level       int
+	experience  int
+	gamesPlayed int
+	gamesWon    int
+	ratings     map[models.GameMode]int
+}
+
+type leaderboardView struct {
+	mode models.GameMode
+	sort LeaderboardSort
+}
+
+// Leaderboard keeps every view sorted as players are saved, so a page is a
+// slice of an ordered list rather than a scan of the players directory.
+// Players are ranked by rating in every mode added and every mode a stored
+// player has a rating in.
+type Leaderboard struct {
+	records map[string]*leaderboardRecord
+	views   map[leaderboardView][]*leaderboardRecord
+	mutex   sync.RWMutex
+}
+
+func NewLeaderboard() *Leaderboard {
+	lb := &Leaderboard{
+		records: make(map[string]*leaderboardRecord),
+		views:   make(map[leaderboardView][]*leaderboardRecord),
+	}
+	lb.addView(leaderboardView{"", SortByWins})
+	lb.addView(leaderboardView{"", SortByLevel})
+	return lb
+}
+
+// AddModes ranks players by rating in modes, such as those games can be
+// created in, whether or not anyone has played them yet
+func (lb *Leaderboard) AddModes(modes ...models.GameMode) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+
+	for _, mode := range modes {
+		lb.addView(leaderboardView{mode, SortByRating})
+	}
+}
+
+// addView orders every indexed player in a view it does not have yet.
+// Callers hold the write lock.
+func (lb *Leaderboard) addView(view leaderboardView) {
+	if _, exists := lb.views[view]; exists {
+		return
+	}
+
+	ordered := make([]*leaderboardRecord, 0, len(lb.records))
+	for _, record := range lb.records {
+		ordered = append(ordered, record)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		comparison := compareScores(view.score(ordered[i]), view.score(ordered[j]))
+		if comparison != 0 {
+			return comparison > 0
+		}
+		return ordered[i].id < ordered[j].id
+	})
+	lb.views[view] = ordered
+}
+
+// Update inserts or repositions a player in every view
+func (lb *Leaderboard) Update(player *models.Player) {
+	record := &leaderboardRecord{
+		id:          player.ID,
+		username:    player.Username,
+		level:       player.Level,
+		experience:  player.Experience,
+		gamesPlayed: player.Stats.GamesPlayed,
+		gamesWon:    player.Stats.GamesWon,
+		ratings:     make(map[models.GameMode]int),
+	}
+	for mode, rating := range player.Ratings {
+		record.ratings[mode] = rating.Value
+	}
+
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+
+	for mode := range record.ratings {
+		lb.addView(leaderboardView{mode, SortByRating})
+	}
+
+	old, exists := lb.records[player.ID]
+	for view, ordered := range lb.views {
+		if exists {
+			index := lb.search(view, ordered, old)
+			ordered = append(ordered[:index], ordered[index+1:]...)
+		}
+
+		index := lb.search(view, ordered, record)
+		ordered = append(ordered, nil)
+		copy(ordered[index+1:], ordered[index:])
+		ordered[index] = record
+		lb.views[view] = ordered
+	}
+	lb.records[player.ID] = record
+}
+
+// Page returns one page of a view along with the requesting player's row
+func (lb *Leaderboard) Page(mode models.GameMode, sortBy LeaderboardSort, page, pageSize int, playerID string) (*LeaderboardPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultLeaderboardPageSize
+	}
+	if pageSize > MaxLeaderboardPageSize {
+		pageSize = MaxLeaderboardPageSize
+	}
+
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	view, err := lb.resolveView(mode, sortBy)
+	if err != nil {
+		return nil, err
+	}
+	ordered := lb.views[view]
+	result := &LeaderboardPage{
+		Mode:         view.mode,
+		Sort:         view.sort,
+		Page:         page,
+		PageSize:     pageSize,
+		TotalPlayers: len(ordered),
+		TotalPages:   (len(ordered) + pageSize - 1) / pageSize,
+		Entries:      make([]LeaderboardEntry, 0, pageSize),
+	}
+
+	for i := (page - 1) * pageSize; i < len(ordered) && i < page*pageSize; i++ {
+		result.Entries = append(result.Entries, lb.entry(view, ordered, i))
+	}
+
+	if record, exists := lb.records[playerID]; exists {
+		own := lb.entry(view, ordered, lb.search(view, ordered, record))
+		result.Own = &own
+	}
+
+	return result, nil
+}
+
+// resolveView finds the view a page is read from. Callers hold the lock.
+func (lb *Leaderboard) resolveView(mode models.GameMode, sortBy LeaderboardSort) (leaderboardView, error) {
+	switch sortBy {
+	case SortByRating, "":
+		if mode == "" {
+			mode = models.SimpleMode
+		}
+		view := leaderboardView{mode, SortByRating}
+		if _, exists := lb.views[view]; !exists {
+			return leaderboardView{}, errors.New("invalid game mode")
+		}
+		return view, nil
+	case SortByWins, SortByLevel:
+		// Wins and levels are counted across modes
+		return leaderboardView{"", sortBy}, nil
+	default:
+		return leaderboardView{}, errors.New("invalid sort")
+	}
+}
+
+func (lb *Leaderboard) entry(view leaderboardView, ordered []*leaderboardRecord, index int) LeaderboardEntry {
+	record := ordered[index]
+	score := view.score(record)
+
+	// The rank is the position of the first record with the same score
+	first := sort.Search(index+1, func(i int) bool {
+		return compareScores(view.score(ordered[i]), score) <= 0
+	})
+
+	return LeaderboardEntry{
+		Rank:        first + 1,
+		PlayerID:    record.id,
+		Username:    record.username,
+		Level:       record.level,
+		Experience:  record.experience,
+		GamesPlayed: record.gamesPlayed,
+		GamesWon:    record.gamesWon,
+		Rating:      record.rating(view.mode),
+	}
+}
+
+// search finds where record belongs in a view ordered by score, highest
+// first, with ties broken by player ID
+func (lb *Leaderboard) search(view leaderboardView, ordered []*leaderboardRecord, record *leaderboardRecord) int {
+	score := view.score(record)
+	return sort.Search(len(ordered), func(i int) bool {
+		comparison := compareScores(view.score(ordered[i]), score)
+		if comparison != 0 {
+			return comparison < 0
+		}
+		return ordered[i].id >= record.id
+	})
+}
+
+// score is the sort key of a record, compared element by element
+func (view leaderboardView) score(record *leaderboardRecord) [2]int {
+	switch view.sort {
+	case SortByWins:
+		return [2]int{record.gamesWon, 0}
+	case SortByLevel:
+		return [2]int{record.level, record.experience}
+	default:
+		return [2]int{record.rating(view.mode), 0}
+	}
+}
+
+func (record *leaderboardRecord) rating(mode models.GameMode) int {
+	if rating, exists := record.ratings[mode]; exists {
+		return rating
+	}
+	return models.DefaultRating
+}
+
+func compareScores(a, b [2]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
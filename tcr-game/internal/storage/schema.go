@@ -0,0 +1,43 @@
+// internal/storage/schema.go - Schema validation errors for game data files
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaError describes one invalid field of one entry in a data file
+type SchemaError struct {
+	Index   int    `json:"index"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e SchemaError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("entry %d: %s", e.Index, e.Message)
+	}
+	return fmt.Sprintf("entry %d: %s: %s", e.Index, e.Field, e.Message)
+}
+
+// SchemaErrors collects every problem found in a data file
+type SchemaErrors struct {
+	File   string        `json:"file"`
+	Errors []SchemaError `json:"errors"`
+}
+
+func (e *SchemaErrors) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s: %d schema error(s): %s", e.File, len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *SchemaErrors) add(index int, field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, SchemaError{
+		Index:   index,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
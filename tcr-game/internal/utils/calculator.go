@@ -2,18 +2,16 @@
 package utils
 
 import (
-	"math/rand"
-	
 	"tcr-game/internal/models"
 )
 
-func CalculateDamage(attacker *models.Troop, defender models.Attackable, critMultiplier float64) (int, bool) {
+func CalculateDamage(attacker *models.Troop, defender models.Attackable, critMultiplier float64, rng models.RandomSource) (int, bool) {
 	baseDamage := attacker.Attack - defender.GetDefense()
 	if baseDamage < 0 {
 		baseDamage = 0
 	}
 	
-	criticalHit := rand.Float64() < attacker.CritChance
+	criticalHit := rng.Float64() < attacker.CritChance
 	damage := baseDamage
 	
 	if criticalHit {
@@ -23,14 +21,7 @@ func CalculateDamage(attacker *models.Troop, defender models.Attackable, critMul
 	return damage, criticalHit
 }
 
+// CalculateRequiredExp returns the EXP needed to go from level to level+1
 func CalculateRequiredExp(level int) int {
-	// Each level requires 10% more EXP than the previous
-	baseExp := 100
-	required := baseExp
-	
-	for i := 1; i < level; i++ {
-		required = int(float64(required) * 1.1)
-	}
-	
-	return required
-}
\ No newline at end of file
+	return models.ExpToNextLevel(level)
+}
@@ -21,7 +21,10 @@ func main() {
 	}
 
 	// Create and start server
-	srv := server.New(cfg)
+	srv, err := server.New(cfg, *configPath)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
 	log.Printf("Starting TCR Game Server on port %s", *port)
 	
 	if err := srv.Start(*port); err != nil {
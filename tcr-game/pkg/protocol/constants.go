@@ -9,16 +9,39 @@ const (
 	MsgTypeJoinGame       = "join_game"
 	MsgTypeSimpleAction   = "simple_action"
 	MsgTypeEnhancedAction = "enhanced_action"
+	MsgTypeAction         = "action"
+	MsgTypeAck            = "ack"
 	MsgTypeGameState      = "game_state"
 	MsgTypeActionResult   = "action_result"
 	MsgTypePlayerJoined   = "player_joined"
+	MsgTypeGameStarted    = "game_started"
+	MsgTypeTurnChanged    = "turn_changed"
+	MsgTypeAttackMade     = "attack_made"
+	MsgTypeTowerDestroyed = "tower_destroyed"
+	MsgTypeManaUpdated    = "mana_updated"
+	MsgTypeArenaTick      = "arena_tick"
+	MsgTypePhaseChanged   = "phase_changed"
+	MsgTypeDraftUpdate    = "draft_update"
+	MsgTypeDraft          = "draft"
+	MsgTypePlayerEliminated = "player_eliminated"
+	MsgTypePlayerDisconnected = "player_disconnected"
+	MsgTypePlayerReconnected  = "player_reconnected"
 	MsgTypeGameEnd        = "game_end"
+	MsgTypeMatchRewards   = "match_rewards"
+	MsgTypeQueueStatus    = "queue_status"
+	MsgTypeMatchFound     = "match_found"
+	MsgTypeGetState       = "get_state"
+	MsgTypeGetStatus      = "get_status"
+	MsgTypeError          = "error"
 	MsgTypePing           = "ping"
 	MsgTypePong           = "pong"
 	
 	// Game modes
 	GameModeSimple   = "simple"
 	GameModeEnhanced = "enhanced"
+	GameModeDraft    = "draft"
+	GameModeTeam     = "team"
+	GameModeFFA      = "ffa"
 	
 	// Tower types
 	TowerTypeKing  = "king_tower"
@@ -27,9 +50,12 @@ const (
 	// Action types
 	ActionTypeAttack     = "attack"
 	ActionTypeSpawnTroop = "spawn_troop"
+	ActionTypePick       = "pick"
+	ActionTypeBan        = "ban"
 	
 	// Game states
 	GameStateWaiting    = "waiting"
+	GameStateDrafting   = "drafting"
 	GameStateInProgress = "in_progress"
 	GameStateFinished   = "finished"
 )
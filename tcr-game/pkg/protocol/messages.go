@@ -1,7 +1,10 @@
 // pkg/protocol/messages.go - Game protocol messages
 package protocol
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Client to Server messages
 type LoginMessage struct {
@@ -10,30 +13,47 @@ type LoginMessage struct {
 	Password string `json:"password"`
 }
 
+// Messages sent over a game's WebSocket may carry a request ID, which the
+// server's ack or error reply repeats
 type CreateGameMessage struct {
-	Type   string `json:"type"`
-	GameID string `json:"game_id"`
-	Mode   string `json:"mode"`
+	Type      string `json:"type"`
+	RequestID string `json:"request_id,omitempty"`
+	GameID    string `json:"game_id"`
+	Mode      string `json:"mode"`
+	Players   int    `json:"players,omitempty"`
 }
 
 type JoinGameMessage struct {
-	Type   string `json:"type"`
-	GameID string `json:"game_id"`
+	Type      string `json:"type"`
+	RequestID string `json:"request_id,omitempty"`
+	GameID    string `json:"game_id"`
 }
 
 type SimpleActionMessage struct {
-	Type        string `json:"type"`
-	TroopID     string `json:"troop_id"`
-	TargetTower int    `json:"target_tower"`
+	Type         string `json:"type"`
+	RequestID    string `json:"request_id,omitempty"`
+	TroopID      string `json:"troop_id"`
+	TargetPlayer string `json:"target_player,omitempty"`
+	TargetTower  int    `json:"target_tower"`
 }
 
 type EnhancedActionMessage struct {
-	Type        string    `json:"type"`
-	TroopID     string    `json:"troop_id"`
-	TargetTower int       `json:"target_tower"`
+	Type         string    `json:"type"`
+	RequestID    string    `json:"request_id,omitempty"`
+	TroopID      string    `json:"troop_id"`
+	TargetPlayer string    `json:"target_player,omitempty"`
+	TargetTower  int       `json:"target_tower"`
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+// ActionMessage sends an action of any mode as the mode's rule set reads it,
+// the same as the body of POST /api/games/{id}/action
+type ActionMessage struct {
+	Type      string          `json:"type"`
+	RequestID string          `json:"request_id,omitempty"`
+	Action    json.RawMessage `json:"action"`
+}
+
 // Server to Client messages
 type LoginResponse struct {
 	Type    string      `json:"type"`
@@ -43,24 +63,159 @@ type LoginResponse struct {
 	Error   string      `json:"error,omitempty"`
 }
 
+// GameStateMessage is a snapshot of a game, up to date with its events
+// through Seq
 type GameStateMessage struct {
 	Type string      `json:"type"`
+	Seq  int64       `json:"seq"`
 	Data interface{} `json:"data"`
 }
 
 type ActionResultMessage struct {
-	Type   string      `json:"type"`
-	Result interface{} `json:"result"`
+	Type     string      `json:"type"`
+	Seq      int64       `json:"seq"`
+	PlayerID string      `json:"player_id,omitempty"`
+	Result   interface{} `json:"result"`
 }
 
 type PlayerJoinedMessage struct {
 	Type     string `json:"type"`
+	Seq      int64  `json:"seq"`
 	PlayerID string `json:"player_id"`
 	Username string `json:"username"`
+	Team     int    `json:"team"`
 }
 
 type GameEndMessage struct {
-	Type   string `json:"type"`
-	Winner string `json:"winner,omitempty"`
-	Reason string `json:"reason"`
-}
\ No newline at end of file
+	Type       string         `json:"type"`
+	Seq        int64          `json:"seq"`
+	Winner     string         `json:"winner,omitempty"`
+	WinnerTeam int            `json:"winner_team"`
+	Places     map[string]int `json:"places,omitempty"`
+	Reason     string         `json:"reason"`
+}
+
+type PlayerEliminatedMessage struct {
+	Type     string `json:"type"`
+	Seq      int64  `json:"seq"`
+	PlayerID string `json:"player_id"`
+	Team     int    `json:"team"`
+	Place    int    `json:"place"`
+	Reason   string `json:"reason"`
+}
+
+type PlayerDisconnectedMessage struct {
+	Type         string `json:"type"`
+	Seq          int64  `json:"seq"`
+	PlayerID     string `json:"player_id"`
+	Team         int    `json:"team"`
+	GraceSeconds int    `json:"grace_seconds"`
+}
+
+type PlayerReconnectedMessage struct {
+	Type     string `json:"type"`
+	Seq      int64  `json:"seq"`
+	PlayerID string `json:"player_id"`
+	Team     int    `json:"team"`
+}
+
+type GameStartedMessage struct {
+	Type    string `json:"type"`
+	Seq     int64  `json:"seq"`
+	Mode    string `json:"mode"`
+	Players int    `json:"players"`
+	State   string `json:"state"`
+}
+
+type TurnChangedMessage struct {
+	Type                 string `json:"type"`
+	Seq                  int64  `json:"seq"`
+	CurrentPlayer        string `json:"current_player"`
+	Team                 int    `json:"team"`
+	TurnRemainingSeconds int    `json:"turn_remaining_seconds"`
+	Timeouts             int    `json:"timeouts"`
+	Reason               string `json:"reason"`
+}
+
+type AttackMadeMessage struct {
+	Type   string      `json:"type"`
+	Seq    int64       `json:"seq"`
+	Battle interface{} `json:"battle"`
+}
+
+type TowerDestroyedMessage struct {
+	Type       string `json:"type"`
+	Seq        int64  `json:"seq"`
+	PlayerID   string `json:"player_id"`
+	Team       int    `json:"team"`
+	TowerIndex int    `json:"tower_index"`
+}
+
+type ManaUpdatedMessage struct {
+	Type     string `json:"type"`
+	Seq      int64  `json:"seq"`
+	PlayerID string `json:"player_id"`
+	Mana     int    `json:"mana"`
+}
+
+type ArenaTickMessage struct {
+	Type string      `json:"type"`
+	Seq  int64       `json:"seq"`
+	Tick interface{} `json:"tick"`
+}
+
+type PhaseChangedMessage struct {
+	Type                 string  `json:"type"`
+	Seq                  int64   `json:"seq"`
+	Phase                string  `json:"phase"`
+	TimeRemainingSeconds int     `json:"time_remaining_seconds"`
+	ManaRegenPerSecond   float64 `json:"mana_regen_per_second"`
+}
+
+type DraftUpdateMessage struct {
+	Type  string      `json:"type"`
+	Seq   int64       `json:"seq"`
+	Draft interface{} `json:"draft"`
+}
+
+type MatchRewardsMessage struct {
+	Type    string      `json:"type"`
+	Seq     int64       `json:"seq"`
+	Rewards interface{} `json:"rewards"`
+}
+
+type QueueStatusMessage struct {
+	Type   string      `json:"type"`
+	Status interface{} `json:"status"`
+}
+
+type MatchFoundMessage struct {
+	Type     string       `json:"type"`
+	GameID   string       `json:"game_id"`
+	Mode     string       `json:"mode"`
+	Opponent OpponentInfo `json:"opponent"`
+}
+
+type OpponentInfo struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Level    int    `json:"level"`
+	Rating   int    `json:"rating"`
+}
+
+// AckMessage answers a request sent over a WebSocket with its result
+type AckMessage struct {
+	Type      string      `json:"type"`
+	RequestID string      `json:"request_id,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+}
+
+type ErrorMessage struct {
+	Type      string `json:"type"`
+	RequestID string `json:"request_id,omitempty"`
+	Error     string `json:"error"`
+}
+
+type PongMessage struct {
+	Type string `json:"type"`
+}
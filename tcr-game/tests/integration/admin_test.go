@@ -0,0 +1,40 @@
+// tests/integration/admin_test.go - Admin endpoint access tests
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAdmin_BalanceIsLimitedToAdmins(t *testing.T) {
+	httpServer := startServer(t)
+	tokens := map[string]string{
+		"alice": login(t, httpServer, "alice"),
+		"admin": login(t, httpServer, "admin"),
+	}
+
+	cases := []struct {
+		method, path, token string
+		status              int
+	}{
+		{http.MethodGet, "/api/admin/balance", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/admin/balance", tokens["alice"], http.StatusForbidden},
+		{http.MethodPost, "/api/admin/balance/reload", tokens["alice"], http.StatusForbidden},
+		{http.MethodGet, "/api/admin/balance", tokens["admin"], http.StatusOK},
+		{http.MethodPost, "/api/admin/balance/reload", tokens["admin"], http.StatusOK},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, httpServer.URL+c.path, nil)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to call %s: %v", c.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("Expected %s %s to answer %d, got %d", c.method, c.path, c.status, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+// tests/integration/websocket_test.go - Playing a game over WebSockets
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"tcr-game/config"
+	"tcr-game/internal/server"
+)
+
+const serverConfig = `{
+	"server": {"admins": ["admin"]},
+	"game": {
+		"simple": {"max_players": 2, "turn_time_seconds": 30, "exp_win": 20, "exp_draw": 5},
+		"enhanced": {"game_duration_seconds": 180, "mana_regen_per_second": 1.0, "crit_multiplier": 1.2, "exp_win": 30, "exp_draw": 10},
+		"draft": {"bans_per_player": 1, "picks_per_player": 3, "pick_time_seconds": 20},
+		"team": {"turn_time_seconds": 30},
+		"ffa": {"turn_time_seconds": 30}
+	}
+}`
+
+// startServer serves the game with the repository's balance data and
+// players and games stored in a temporary directory
+func startServer(t *testing.T) *httptest.Server {
+	return startServerWith(t, config.WebSocketConfig{})
+}
+
+func startServerWith(t *testing.T, webSocket config.WebSocketConfig) *httptest.Server {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "game_config.json")
+	if err := os.WriteFile(configPath, []byte(serverConfig), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Database = config.DatabaseConfig{
+		TroopsFile:   "../../data/troops.json",
+		TowersFile:   "../../data/towers.json",
+		UpgradesFile: "../../data/upgrades.json",
+		PlayersDir:   filepath.Join(dir, "players"),
+		GamesDir:     filepath.Join(dir, "games"),
+	}
+	cfg.Server.WebSocket = webSocket
+
+	srv, err := server.New(cfg, configPath)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	httpServer := httptest.NewServer(srv.Handler())
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+// login registers a player and returns their session token
+func login(t *testing.T, httpServer *httptest.Server, username string) string {
+	body, _ := json.Marshal(map[string]string{"username": username, "password": "secret"})
+	for _, path := range []string{"/api/register", "/api/login"} {
+		resp, err := http.Post(httpServer.URL+path, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to call %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected %s to succeed, got %d", path, resp.StatusCode)
+		}
+
+		var response struct {
+			Token string `json:"token"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		if response.Token != "" {
+			return response.Token
+		}
+	}
+	t.Fatalf("Expected a token for %s", username)
+	return ""
+}
+
+// createGame has a player create a game of a mode over HTTP and take its first seat
+func createGame(t *testing.T, httpServer *httptest.Server, token, gameID, mode string) {
+	body, _ := json.Marshal(map[string]string{"game_id": gameID, "mode": mode})
+	req, _ := http.NewRequest(http.MethodPost, httpServer.URL+"/api/games", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to create game %s: %v", gameID, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected game %s to be created, got %d", gameID, resp.StatusCode)
+	}
+}
+
+// gameSocket is a client connected to a game's WebSocket
+type gameSocket struct {
+	t    *testing.T
+	conn *websocket.Conn
+}
+
+func dialGame(t *testing.T, httpServer *httptest.Server, gameID, token string) *gameSocket {
+	return dialURL(t, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/ws/"+gameID+"?token="+token)
+}
+
+// resumeGame reconnects to a game after the event numbered since
+func resumeGame(t *testing.T, httpServer *httptest.Server, gameID, token string, since int64) *gameSocket {
+	return dialURL(t, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/ws/"+gameID+"?token="+token+"&since="+strconv.FormatInt(since, 10))
+}
+
+func dialURL(t *testing.T, url string) *gameSocket {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to %s: %v", url, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &gameSocket{t: t, conn: conn}
+}
+
+func (gs *gameSocket) send(message interface{}) {
+	if err := gs.conn.WriteJSON(message); err != nil {
+		gs.t.Fatalf("Failed to send %v: %v", message, err)
+	}
+}
+
+// await reads messages until one matches, skipping the rest
+func (gs *gameSocket) await(match func(message map[string]interface{}) bool) map[string]interface{} {
+	gs.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var message map[string]interface{}
+		if err := gs.conn.ReadJSON(&message); err != nil {
+			gs.t.Fatalf("Failed to read message: %v", err)
+		}
+		if match(message) {
+			return message
+		}
+	}
+}
+
+// reply waits for the ack or error answering a request
+func (gs *gameSocket) reply(requestID string) map[string]interface{} {
+	return gs.await(func(message map[string]interface{}) bool {
+		return message["request_id"] == requestID
+	})
+}
+
+func (gs *gameSocket) awaitType(messageType string) map[string]interface{} {
+	return gs.await(func(message map[string]interface{}) bool {
+		return message["type"] == messageType
+	})
+}
+
+func TestWebSocket_PlaysAGame(t *testing.T) {
+	httpServer := startServer(t)
+	aliceToken := login(t, httpServer, "alice")
+	createGame(t, httpServer, aliceToken, "ws_game", "simple")
+	alice := dialGame(t, httpServer, "ws_game", aliceToken)
+	bob := dialGame(t, httpServer, "ws_game", login(t, httpServer, "bob"))
+
+	bob.send(map[string]interface{}{"type": "join_game", "request_id": "j1", "game_id": "ws_game"})
+	reply := bob.reply("j1")
+	if reply["type"] != "ack" {
+		t.Fatalf("Expected bob to join, got %v", reply)
+	}
+	alice.awaitType("game_started")
+
+	// Alice goes first, with the troops she was dealt
+	players := reply["result"].(map[string]interface{})["players"].([]interface{})
+	if _, leaked := players[0].(map[string]interface{})["password"]; leaked {
+		t.Fatalf("Expected the join ack to leave out player passwords")
+	}
+	troops := players[0].(map[string]interface{})["troops"].([]interface{})
+	troopID := troops[0].(map[string]interface{})["id"].(string)
+
+	bob.send(map[string]interface{}{"type": "simple_action", "request_id": "a1", "troop_id": troopID, "target_tower": 0})
+	reply = bob.reply("a1")
+	if result := reply["result"].(map[string]interface{}); reply["type"] != "ack" || result["success"] != false {
+		t.Errorf("Expected bob's out of turn attack to be acked as rejected, got %v", reply)
+	}
+
+	// Both players hear about the attack, alice before her ack
+	alice.send(map[string]interface{}{"type": "simple_action", "request_id": "a2", "troop_id": troopID, "target_tower": 0})
+	for _, socket := range []*gameSocket{alice, bob} {
+		attack := socket.awaitType("attack_made")
+		if battle := attack["battle"].(map[string]interface{}); battle["troop_used"] == nil {
+			t.Errorf("Expected the attack to carry the battle, got %v", attack)
+		}
+	}
+	reply = alice.reply("a2")
+	if result := reply["result"].(map[string]interface{}); reply["type"] != "ack" || result["success"] != true {
+		t.Errorf("Expected alice's attack to be played, got %v", reply)
+	}
+}
+
+func TestWebSocket_RepliesWithErrors(t *testing.T) {
+	httpServer := startServer(t)
+	token := login(t, httpServer, "alice")
+	createGame(t, httpServer, token, "ws_errors", "simple")
+	alice := dialGame(t, httpServer, "ws_errors", token)
+
+	requests := []map[string]interface{}{
+		{"type": "join_game", "request_id": "r1"},
+		{"type": "join_game", "request_id": "r2", "game_id": "elsewhere"},
+		{"type": "create_game", "request_id": "r3", "mode": "simple"},
+		{"type": "launch_rockets", "request_id": "r4"},
+	}
+	for _, request := range requests {
+		alice.send(request)
+		requestID := request["request_id"].(string)
+		if reply := alice.reply(requestID); reply["type"] != "error" || reply["error"] == "" {
+			t.Errorf("Expected %s to be answered with an error, got %v", requestID, reply)
+		}
+	}
+
+	alice.send(map[string]interface{}{"type": "ping"})
+	alice.awaitType("pong")
+}
+
+func TestWebSocket_MessagesArriveInOrder(t *testing.T) {
+	httpServer := startServer(t)
+	token := login(t, httpServer, "alice")
+	createGame(t, httpServer, token, "ws_order", "simple")
+	alice := dialGame(t, httpServer, "ws_order", token)
+
+	// Requests are answered in the order they were sent
+	for i := 0; i < 50; i++ {
+		alice.send(map[string]interface{}{"type": "get_nothing", "request_id": strconv.Itoa(i)})
+	}
+	for i := 0; i < 50; i++ {
+		reply := alice.awaitType("error")
+		if reply["request_id"] != strconv.Itoa(i) {
+			t.Fatalf("Expected the reply to request %d, got %v", i, reply)
+		}
+	}
+
+	// An action's events arrive in the order they were published
+	bob := dialGame(t, httpServer, "ws_order", login(t, httpServer, "bob"))
+	bob.send(map[string]interface{}{"type": "join_game", "request_id": "j1"})
+	reply := bob.reply("j1")
+
+	players := reply["result"].(map[string]interface{})["players"].([]interface{})
+	troops := players[0].(map[string]interface{})["troops"].([]interface{})
+	alice.send(map[string]interface{}{"type": "simple_action", "request_id": "a1", "troop_id": troops[0].(map[string]interface{})["id"], "target_tower": 0})
+
+	var received []string
+	bob.await(func(message map[string]interface{}) bool {
+		switch message["type"] {
+		case "action_result", "attack_made", "turn_changed":
+			received = append(received, message["type"].(string))
+		}
+		return message["type"] == "turn_changed"
+	})
+	expected := []string{"action_result", "attack_made", "turn_changed"}
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, received)
+	}
+}
+
+// dropAfterCreating has alice create a game and disconnect, then bob join
+// it. It returns alice's token, the seq of the last event she saw and
+// bob's socket.
+func dropAfterCreating(t *testing.T, httpServer *httptest.Server, gameID string) (string, int64, *gameSocket) {
+	token := login(t, httpServer, "alice")
+	createGame(t, httpServer, token, gameID, "simple")
+	alice := dialGame(t, httpServer, gameID, token)
+
+	lastSeq := int64(alice.awaitType("game_state")["seq"].(float64))
+	alice.conn.Close()
+
+	bob := dialGame(t, httpServer, gameID, login(t, httpServer, "bob"))
+	bob.send(map[string]interface{}{"type": "join_game", "request_id": "j1"})
+	bob.awaitType("game_started")
+	return token, lastSeq, bob
+}
+
+func TestWebSocket_ResumesMissedEvents(t *testing.T) {
+	httpServer := startServer(t)
+	token, lastSeq, bob := dropAfterCreating(t, httpServer, "ws_resume")
+	if lastSeq == 0 {
+		t.Fatalf("Expected alice to see her own join")
+	}
+
+	// Bob tries to play out of turn while alice is away
+	bob.send(map[string]interface{}{"type": "simple_action", "request_id": "a1", "troop_id": "goblin", "target_tower": 0})
+	bob.reply("a1")
+
+	// Alice gets what she missed, in order, instead of a snapshot
+	alice := resumeGame(t, httpServer, "ws_resume", token, lastSeq)
+	next := lastSeq + 1
+	bobJoined := false
+	alice.await(func(message map[string]interface{}) bool {
+		if message["seq"] != float64(next) {
+			t.Fatalf("Expected event %d, got %v", next, message)
+		}
+		next++
+		if message["type"] == "player_joined" && message["username"] == "bob" {
+			bobJoined = true
+		}
+		return message["type"] == "action_result"
+	})
+	if !bobJoined {
+		t.Errorf("Expected bob's join among the missed events")
+	}
+}
+
+func TestWebSocket_ResumesFarBehindWithSnapshot(t *testing.T) {
+	httpServer := startServerWith(t, config.WebSocketConfig{ResumeBuffer: 1})
+	token, lastSeq, _ := dropAfterCreating(t, httpServer, "ws_snapshot")
+
+	alice := resumeGame(t, httpServer, "ws_snapshot", token, lastSeq)
+	snapshot := alice.await(func(map[string]interface{}) bool { return true })
+	if snapshot["type"] != "game_state" || snapshot["seq"].(float64) < float64(lastSeq+2) {
+		t.Errorf("Expected a snapshot after bob's join and the start, got %v", snapshot)
+	}
+}
+
+func TestWebSocket_TellsOpponentsOfDisconnects(t *testing.T) {
+	httpServer := startServer(t)
+	aliceToken := login(t, httpServer, "alice")
+	createGame(t, httpServer, aliceToken, "ws_presence", "simple")
+	alice := dialGame(t, httpServer, "ws_presence", aliceToken)
+	bobToken := login(t, httpServer, "bob")
+	bob := dialGame(t, httpServer, "ws_presence", bobToken)
+
+	bob.send(map[string]interface{}{"type": "join_game", "request_id": "j1"})
+	bob.reply("j1")
+
+	bob.conn.Close()
+	if left := alice.awaitType("player_disconnected"); left["player_id"] == nil || left["grace_seconds"] != float64(0) {
+		t.Errorf("Expected bob's disconnect without a grace period, got %v", left)
+	}
+
+	dialGame(t, httpServer, "ws_presence", bobToken)
+	alice.awaitType("player_reconnected")
+	alice.send(map[string]interface{}{"type": "get_state"})
+	state := alice.awaitType("game_state")["data"].(map[string]interface{})
+	for playerID, presence := range state["presence"].(map[string]interface{}) {
+		if presence.(map[string]interface{})["connected"] != true {
+			t.Errorf("Expected %s to be shown connected, got %v", playerID, presence)
+		}
+	}
+}
+
+func TestWebSocket_DisconnectsSilentClients(t *testing.T) {
+	httpServer := startServerWith(t, config.WebSocketConfig{PingInterval: 50, PongTimeout: 200})
+	token := login(t, httpServer, "alice")
+	createGame(t, httpServer, token, "ws_heartbeat", "simple")
+
+	// A client answering pings stays connected past the pong timeout
+	alive := dialGame(t, httpServer, "ws_heartbeat", token)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		alive.conn.WriteJSON(map[string]string{"type": "ping"})
+	}()
+	alive.awaitType("pong")
+
+	// A client that never answers is disconnected
+	silent := dialGame(t, httpServer, "ws_heartbeat", token)
+	silent.conn.SetPingHandler(func(string) error { return nil })
+	silent.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var message map[string]interface{}
+		if err := silent.conn.ReadJSON(&message); err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				t.Fatalf("Expected the silent client to be disconnected")
+			}
+			break
+		}
+	}
+}
+
+func TestWebSocket_OnlyAdmitsAGamesPlayers(t *testing.T) {
+	httpServer := startServer(t)
+	tokens := map[string]string{}
+	for _, username := range []string{"alice", "bob", "carol"} {
+		tokens[username] = login(t, httpServer, username)
+	}
+	createGame(t, httpServer, tokens["alice"], "ws_members", "simple")
+
+	// Anyone may connect to a game waiting for players, to join it
+	bob := dialGame(t, httpServer, "ws_members", tokens["bob"])
+	bob.send(map[string]interface{}{"type": "join_game", "request_id": "j1"})
+	bob.reply("j1")
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	cases := []struct {
+		gameID, username string
+		status           int
+	}{
+		{"ws_nowhere", "alice", http.StatusNotFound},
+		{"ws_members", "carol", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL+"/ws/"+c.gameID+"?token="+tokens[c.username], nil)
+		if err == nil {
+			conn.Close()
+			t.Errorf("Expected %s to be refused a socket to %s", c.username, c.gameID)
+			continue
+		}
+		if resp == nil || resp.StatusCode != c.status {
+			t.Errorf("Expected %s to be refused a socket to %s with %d, got %v", c.username, c.gameID, c.status, resp)
+		}
+	}
+
+	dialGame(t, httpServer, "ws_members", tokens["alice"]).awaitType("game_state")
+}
+
+func TestWebSocketConfig_Validate(t *testing.T) {
+	cfg := config.WebSocketConfig{SlowConsumer: "buffer"}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected an unknown slow consumer policy to be rejected")
+	}
+	cfg = config.WebSocketConfig{PingInterval: 1000, PongTimeout: 500}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected pings slower than the pong timeout to be rejected")
+	}
+}
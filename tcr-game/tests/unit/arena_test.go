@@ -0,0 +1,155 @@
+// tests/unit/arena_test.go - Enhanced mode arena simulation tests
+package unit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+func newArenaGame(seed int64) *models.Game {
+	gameObj := models.NewGameWithSeed("arena_game", models.EnhancedMode, seed)
+	gameObj.AddPlayer(models.NewPlayer("p1", "player1", "pass1"))
+	gameObj.AddPlayer(models.NewPlayer("p2", "player2", "pass2"))
+	gameObj.Start()
+	return gameObj
+}
+
+func TestArena_TroopWalksToLaneTower(t *testing.T) {
+	gameObj := newArenaGame(1)
+	arena := game.NewArena(gameObj, game.NewBattleEngine(1.2), game.DefaultArenaConfig())
+	knight := &models.Troop{ID: "knight", HP: 150, MaxHP: 150, Attack: 35, Defense: 10}
+
+	unit, err := arena.Spawn(gameObj.Players[0], knight, game.RightLane)
+	if err != nil {
+		t.Fatalf("Failed to spawn: %v", err)
+	}
+	start := unit.Position
+
+	arena.Step()
+	if unit.Position <= start {
+		t.Errorf("Expected unit to advance from %d, got %d", start, unit.Position)
+	}
+
+	for i := 0; i < 50 && gameObj.Players[1].Towers[1].HP == gameObj.Players[1].Towers[1].MaxHP; i++ {
+		arena.Step()
+	}
+
+	if gameObj.Players[1].Towers[1].HP != gameObj.Players[1].Towers[1].MaxHP-25 {
+		t.Errorf("Expected right guard tower to take 25 damage, got HP %d", gameObj.Players[1].Towers[1].HP)
+	}
+	if gameObj.Players[1].Towers[0].HP != gameObj.Players[1].Towers[0].MaxHP {
+		t.Errorf("Expected left guard tower to be untouched")
+	}
+}
+
+func TestArena_OtherTowerTypesHoldTheirLane(t *testing.T) {
+	gameObj := models.NewGameWithSeed("arena_game", models.EnhancedMode, 1)
+	gameObj.TowerCatalogue = models.DefaultTowerCatalogue()
+	gameObj.TowerCatalogue.Specs["cannon"] = models.TowerSpec{Name: "Cannon", HP: 200, Attack: 30}
+	gameObj.TowerCatalogue.Layout[1].Type = "cannon"
+	gameObj.AddPlayer(models.NewPlayer("p1", "player1", "pass1"))
+	gameObj.AddPlayer(models.NewPlayer("p2", "player2", "pass2"))
+	gameObj.Start()
+	arena := game.NewArena(gameObj, game.NewBattleEngine(1.2), game.DefaultArenaConfig())
+
+	// The left lane is open, but the cannon stands on the right
+	gameObj.Players[1].Towers[0].HP = 0
+	if lane, err := arena.LaneForTarget(gameObj.Players[1], 1); err != nil || lane != game.RightLane {
+		t.Fatalf("Expected the cannon to be reached down its own lane, got %d (%v)", lane, err)
+	}
+	if lane, _ := arena.LaneForTarget(gameObj.Players[1], 2); lane != game.LeftLane {
+		t.Errorf("Expected the king to be reached down the left lane, got %d", lane)
+	}
+}
+
+func TestArena_OpposingTroopsFight(t *testing.T) {
+	gameObj := newArenaGame(1)
+	arena := game.NewArena(gameObj, game.NewBattleEngine(1.2), game.DefaultArenaConfig())
+
+	strong := &models.Troop{ID: "dragon", HP: 200, MaxHP: 200, Attack: 60, Defense: 8}
+	weak := &models.Troop{ID: "goblin", HP: 40, MaxHP: 40, Attack: 10, Defense: 5}
+
+	arena.Spawn(gameObj.Players[0], strong, game.LeftLane)
+	arena.Spawn(gameObj.Players[1], weak, game.LeftLane)
+
+	fought := false
+	for i := 0; i < 20 && len(arena.Units()) == 2; i++ {
+		tick := arena.Step()
+		for _, hit := range tick.Hits {
+			if hit.TargetUnit != "" {
+				fought = true
+			}
+		}
+	}
+
+	units := arena.Units()
+	if !fought || len(units) != 1 || units[0].TroopID != "dragon" {
+		t.Errorf("Expected the dragon to defeat the goblin, got %+v", units)
+	}
+}
+
+func TestEnhancedGameManager_ArenaMatchReplays(t *testing.T) {
+	manager := game.NewEnhancedGameManager(1.0, 5, 1.2, 1)
+	events := game.NewEventManager()
+	manager.SetEventManager(events)
+	var mutex sync.Mutex
+	var published []game.EventType
+	for _, eventType := range []game.EventType{game.EventArenaTick, game.EventTowerDestroyed, game.EventGameEnded} {
+		events.Handle(eventType, func(event game.GameEventData) {
+			mutex.Lock()
+			published = append(published, event.Type)
+			mutex.Unlock()
+		})
+	}
+	gameObj := models.NewGameWithSeed("arena_replay", models.EnhancedMode, 11)
+
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{
+			{ID: "giant", HP: 5000, MaxHP: 5000, Attack: 400, Defense: 5, CritChance: 0.3, ManaCost: 2},
+		}
+		gameObj.AddPlayer(player)
+	}
+
+	if err := manager.StartGame(gameObj); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer manager.CleanupGame(gameObj.ID)
+
+	for _, target := range []int{0, 1} {
+		result, err := manager.ProcessAction(gameObj.ID, "p1", game.EnhancedAction{Type: "spawn_troop", TroopID: "giant", TargetTower: target})
+		if err != nil || !result.Success {
+			t.Fatalf("Spawn failed: %v %+v", err, result)
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := manager.GetGameState(gameObj.ID)
+		if err != nil {
+			t.Fatalf("Failed to get state: %v", err)
+		}
+		if state["state"] == models.Finished {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	state, _ := manager.GetGameState(gameObj.ID)
+	if state["winner"] != "p1" {
+		t.Fatalf("Expected p1 to win by destroying the king tower, got state %v", state["state"])
+	}
+	mutex.Lock()
+	if last := published[len(published)-1]; last != game.EventGameEnded || published[len(published)-2] == game.EventGameEnded {
+		t.Errorf("Expected the deciding tick before game_ended and nothing after, got %v", published[len(published)-3:])
+	}
+	mutex.Unlock()
+
+	if err := game.NewReplayEngine(1.2).Verify(gameObj.Replay); err != nil {
+		t.Errorf("Expected arena replay to verify, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+// tests/unit/balance_test.go - Balance versioning and reload tests
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+const balanceConfig = `{
+	"game": {
+		"simple": {"max_players": 2, "turn_time_seconds": 30},
+		"enhanced": {"game_duration_seconds": 180, "mana_regen_per_second": 1.0, "crit_multiplier": 1.2, "exp_win": 30, "exp_draw": 10}
+	}
+}`
+
+const balanceTowers = `{
+	"towers": {
+		"king_tower": {"name": "King Tower", "hp": 500, "attack": 25, "defense": 15, "crit_chance": 0.1},
+		"guard_tower": {"name": "Guard Tower", "hp": 300, "attack": 20, "defense": 10, "crit_chance": 0.05}
+	}
+}`
+
+func writeBalanceFile(t *testing.T, path, contents string) {
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func goblinTroops(hp string) string {
+	return `[{"id": "goblin", "name": "Goblin", "hp": ` + hp + `, "attack": 30, "defense": 5, "crit_chance": 0.1, "mana_cost": 2}]`
+}
+
+func TestGameEngine_GamesKeepTheirBalanceVersion(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "game_config.json")
+	troopsPath := filepath.Join(dir, "troops.json")
+	towersPath := filepath.Join(dir, "towers.json")
+	writeBalanceFile(t, configPath, balanceConfig)
+	writeBalanceFile(t, troopsPath, goblinTroops("100"))
+	writeBalanceFile(t, towersPath, balanceTowers)
+
+	jsonStorage := storage.NewJSONStorage(dir, troopsPath, towersPath, "")
+	loader := game.NewBalanceLoader(configPath, jsonStorage)
+	balance, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Failed to load balance: %v", err)
+	}
+
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+	oldGame, _ := engine.CreateGame("old", models.SimpleMode)
+
+	// A broken edit is rejected and does not use up a version
+	writeBalanceFile(t, troopsPath, goblinTroops(`"lots"`))
+	if _, err := loader.Load(); err == nil {
+		t.Fatalf("Expected invalid troops to be rejected")
+	}
+
+	writeBalanceFile(t, troopsPath, goblinTroops("250"))
+	reloaded, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Failed to reload balance: %v", err)
+	}
+	if reloaded.Version != 2 {
+		t.Errorf("Expected version 2, got %d", reloaded.Version)
+	}
+	engine.SetBalance(reloaded)
+
+	newGame, _ := engine.CreateGame("new", models.SimpleMode)
+	if oldGame.BalanceVersion != 1 || newGame.BalanceVersion != 2 {
+		t.Errorf("Expected versions 1 and 2, got %d and %d", oldGame.BalanceVersion, newGame.BalanceVersion)
+	}
+
+	player := models.NewPlayer("p1", "player1", "pass1")
+	if err := engine.JoinGame("old", player); err != nil {
+		t.Fatalf("Failed to join game: %v", err)
+	}
+	if player.AvailableTroops[0].MaxHP != 100 {
+		t.Errorf("Expected troops from version 1 with 100 HP, got %d", player.AvailableTroops[0].MaxHP)
+	}
+
+	state, _ := engine.GetGameState("new")
+	if state["balance_version"] != 2 {
+		t.Errorf("Expected balance version 2 in game state, got %v", state["balance_version"])
+	}
+}
@@ -35,7 +35,7 @@ func TestBattleEngine_ExecuteAttack(t *testing.T) {
 	game.State = models.InProgress
 	
 	// Execute attack
-	result, err := engine.ExecuteAttack(game, player1.ID, "goblin", 0)
+	result, err := engine.ExecuteAttack(game, player1.ID, "", "goblin", 0)
 	
 	if err != nil {
 		t.Errorf("Attack failed: %v", err)
@@ -56,6 +56,7 @@ func TestBattleEngine_GetValidTargets(t *testing.T) {
 	
 	player := models.NewPlayer("p1", "player1", "pass1")
 	player.InitializeTowers(nil)
+	game.AddPlayer(player)
 	
 	// Destroy first guard tower
 	player.Towers[0].HP = 0
@@ -66,4 +67,81 @@ func TestBattleEngine_GetValidTargets(t *testing.T) {
 	if len(targets) != 1 || targets[0] != 1 {
 		t.Errorf("Expected valid target [1], got %v", targets)
 	}
-}
\ No newline at end of file
+}
+
+func TestBattleEngine_CriticalHitsFollowSeed(t *testing.T) {
+	engine := game.NewBattleEngine(1.5)
+	troop := &models.Troop{ID: "wizard", Attack: 50, CritChance: 0.5}
+	tower := &models.Tower{Defense: 10, HP: 1000, MaxHP: 1000}
+	
+	roll := func(seed int64) []int {
+		rng := models.NewGameRNG(seed)
+		damages := make([]int, 20)
+		for i := range damages {
+			damages[i], _ = engine.CalculateDamage(troop, tower, true, rng)
+		}
+		return damages
+	}
+	
+	first := roll(7)
+	second := roll(7)
+	
+	crits := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected identical damage sequence for the same seed, got %v and %v", first, second)
+		}
+		if first[i] != 40 && first[i] != 60 {
+			t.Errorf("Expected damage 40 or 60, got %d", first[i])
+		}
+		if first[i] == 60 {
+			crits++
+		}
+	}
+	
+	if crits == 0 || crits == len(first) {
+		t.Errorf("Expected a mix of normal and critical hits, got %d crits out of %d", crits, len(first))
+	}
+}
+
+func TestBattleEngine_TowerFiresBack(t *testing.T) {
+	engine := game.NewBattleEngine(1.2)
+	manager := game.NewSimpleGameManager(2, 30, 0, 1.2)
+	gameObj := models.NewGameWithSeed("test_defense", models.SimpleMode, 3)
+	
+	player1 := models.NewPlayer("p1", "player1", "pass1")
+	player2 := models.NewPlayer("p2", "player2", "pass2")
+	player2.InitializeTowers(nil)
+	
+	goblin := &models.Troop{ID: "goblin", HP: 20, MaxHP: 20, Attack: 30, Defense: 5}
+	player1.AvailableTroops = []*models.Troop{goblin}
+	
+	gameObj.AddPlayer(player1)
+	gameObj.AddPlayer(player2)
+	gameObj.State = models.InProgress
+	
+	result, err := engine.ExecuteAttack(gameObj, player1.ID, "", "goblin", 0)
+	if err != nil {
+		t.Fatalf("Attack failed: %v", err)
+	}
+	
+	// Guard tower: 20 ATK - 5 DEF = 15 damage back
+	if len(result.DefenseHits) != 1 || result.DefenseHits[0].Damage != 15 {
+		t.Fatalf("Expected one 15 damage counterattack, got %+v", result.DefenseHits)
+	}
+	if goblin.HP != 5 || result.TroopDestroyed {
+		t.Errorf("Expected goblin to survive with 5 HP, got %d", goblin.HP)
+	}
+	
+	result, _ = engine.ExecuteAttack(gameObj, player1.ID, "", "goblin", 0)
+	if !result.TroopDestroyed || goblin.IsAlive() {
+		t.Errorf("Expected goblin to be destroyed by the second counterattack")
+	}
+	
+	if _, err := manager.ValidateTroopSelection(player1, "goblin"); err == nil {
+		t.Errorf("Expected destroyed troop to be rejected")
+	}
+	if _, err := engine.ExecuteAttack(gameObj, player1.ID, "", "goblin", 0); err == nil {
+		t.Errorf("Expected attack with a destroyed troop to fail")
+	}
+}
@@ -0,0 +1,80 @@
+// tests/unit/catalogue_test.go - Troop catalogue loading tests
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func loadTroopsFrom(t *testing.T, contents string) (*models.TroopCatalogue, error) {
+	dir := t.TempDir()
+	troopsFile := filepath.Join(dir, "troops.json")
+	if err := os.WriteFile(troopsFile, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write troops file: %v", err)
+	}
+	return storage.NewJSONStorage(dir, troopsFile, "", "").LoadTroops()
+}
+
+func TestJSONStorage_LoadTroopsDefaults(t *testing.T) {
+	catalogue, err := loadTroopsFrom(t, `[
+		{"id": "goblin", "name": "Goblin", "hp": 100, "attack": 30, "defense": 5, "crit_chance": 0.1, "mana_cost": 2},
+		{"id": "giant", "name": "Giant", "hp": 400, "attack": 50, "defense": 20, "crit_chance": 0, "mana_cost": 6, "speed": 2, "unlock_level": 3}
+	]`)
+	if err != nil {
+		t.Fatalf("Failed to load troops: %v", err)
+	}
+
+	goblin, exists := catalogue.Get("goblin")
+	if !exists {
+		t.Fatalf("Expected goblin in catalogue")
+	}
+	if goblin.Speed != models.DefaultTroopSpeed || goblin.UnlockLevel != 1 || goblin.MaxHP != 100 {
+		t.Errorf("Expected defaults, got speed %d unlock %d max hp %d", goblin.Speed, goblin.UnlockLevel, goblin.MaxHP)
+	}
+
+	giant, _ := catalogue.Get("giant")
+	if giant.Speed != 2 || giant.UnlockLevel != 3 {
+		t.Errorf("Expected speed 2 and unlock level 3, got %d and %d", giant.Speed, giant.UnlockLevel)
+	}
+
+	goblin.HP = 1
+	if fresh, _ := catalogue.Get("goblin"); fresh.HP != 100 {
+		t.Errorf("Expected catalogue entries to be copied, got HP %d", fresh.HP)
+	}
+}
+
+func TestJSONStorage_LoadTroopsReportsEverySchemaError(t *testing.T) {
+	_, err := loadTroopsFrom(t, `[
+		{"id": "goblin", "name": "Goblin", "hp": "lots", "attack": 30, "defense": 5, "crit_chance": 0.1, "mana_cost": 2},
+		{"id": "archer", "name": "Archer", "hp": 80, "attack": 40, "defense": 3, "crit_chance": 1.5, "mana_cost": 3},
+		{"id": "archer", "name": "Archer", "hp": 80, "attack": 40, "defense": 3, "crit_chance": 0.1, "mana_cost": 3},
+		{"name": "Nobody", "hp": 80, "attack": 40, "defense": 3, "crit_chance": 0.1, "mana_cost": 3, "colour": "red"}
+	]`)
+
+	var schemaErrors *storage.SchemaErrors
+	if !errors.As(err, &schemaErrors) {
+		t.Fatalf("Expected schema errors, got %v", err)
+	}
+
+	expected := []storage.SchemaError{
+		{Index: 0, Field: "hp"},
+		{Index: 1, Field: "crit_chance"},
+		{Index: 2, Field: "id"},
+		{Index: 3, Field: "id"},
+		{Index: 3, Field: "colour"},
+	}
+	if len(schemaErrors.Errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), schemaErrors)
+	}
+	for i, want := range expected {
+		got := schemaErrors.Errors[i]
+		if got.Index != want.Index || got.Field != want.Field {
+			t.Errorf("Expected error %d at entry %d field %s, got %v", i, want.Index, want.Field, got)
+		}
+	}
+}
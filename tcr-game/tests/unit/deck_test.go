@@ -0,0 +1,148 @@
+// tests/unit/deck_test.go - Deck building and hand cycling tests
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// deckCatalogue has nine troops; t8 unlocks at level 3
+func deckCatalogue() *models.TroopCatalogue {
+	troops := make([]*models.Troop, 9)
+	for i := range troops {
+		id := fmt.Sprintf("t%d", i)
+		troops[i] = models.NewTroop(id, id, 100, 30, 5, 0, 1, "")
+	}
+	troops[8].UnlockLevel = 3
+	return models.NewTroopCatalogue(troops)
+}
+
+func TestValidateDeck(t *testing.T) {
+	catalogue := deckCatalogue()
+	valid := []string{"t0", "t1", "t2", "t3", "t4", "t5", "t6", "t7"}
+	if err := models.ValidateDeck(valid, catalogue, 1); err != nil {
+		t.Errorf("Expected deck to be valid, got %v", err)
+	}
+
+	invalid := map[string][]string{
+		"too small": {"t0", "t1", "t2"},
+		"duplicate": {"t0", "t0", "t2", "t3", "t4", "t5", "t6", "t7"},
+		"unknown":   {"t0", "t1", "t2", "t3", "t4", "t5", "t6", "wizard"},
+		"locked":    {"t0", "t1", "t2", "t3", "t4", "t5", "t6", "t8"},
+	}
+	for name, cards := range invalid {
+		if err := models.ValidateDeck(cards, catalogue, 1); err == nil {
+			t.Errorf("Expected %s deck to be rejected", name)
+		}
+	}
+
+	if deck := models.DefaultDeck(catalogue, 1); len(deck) != models.DeckSize || deck[7] != "t7" {
+		t.Errorf("Expected the first eight unlocked troops, got %v", deck)
+	}
+}
+
+func TestGameEngine_SavedDeckIsDealtAsCyclingHand(t *testing.T) {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Enhanced: config.EnhancedGameConfig{GameDuration: 180, ManaRegen: 1.0, CritMultiplier: 1.2},
+		},
+		Troops: deckCatalogue(),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	player := models.NewPlayer("p1", "player1", "pass1")
+	player.Level = 3
+	jsonStorage.SavePlayer(player)
+	jsonStorage.SavePlayer(models.NewPlayer("p2", "player2", "pass2"))
+
+	cards := []string{"t8", "t7", "t6", "t5", "t4", "t3", "t2", "t1"}
+	if _, err := engine.SaveDeck("p1", 1, models.Deck{Name: "reversed", Cards: cards}); err != nil {
+		t.Fatalf("Failed to save deck: %v", err)
+	}
+	if _, err := engine.SelectDeck("p1", 2); err == nil {
+		t.Errorf("Expected an empty slot not to be selectable")
+	}
+	decks, err := engine.SelectDeck("p1", 1)
+	if err != nil || decks.ActiveSlot != 1 || decks.Active[0] != "t8" {
+		t.Fatalf("Expected the saved deck to be active, got %+v (%v)", decks, err)
+	}
+
+	engine.CreateGame("g1", models.EnhancedMode)
+	defer engine.CleanupGame("g1")
+	for _, id := range []string{"p1", "p2"} {
+		stored, _ := jsonStorage.LoadPlayer(id)
+		if err := engine.JoinGame("g1", stored); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+	}
+
+	// t4 is in the deck but not yet in hand
+	if result, _ := engine.ProcessEnhancedAction("g1", "p1", game.EnhancedAction{Type: "spawn_troop", TroopID: "t4"}); result.Success {
+		t.Errorf("Expected a card outside the hand to be rejected")
+	}
+
+	result, err := engine.ProcessEnhancedAction("g1", "p1", game.EnhancedAction{Type: "spawn_troop", TroopID: "t7"})
+	if err != nil || !result.Success {
+		t.Fatalf("Expected t7 to be playable, got %+v (%v)", result, err)
+	}
+
+	state, _ := engine.GetGameStateFor("g1", "p1")
+	players := state["players"].([]map[string]interface{})
+	if _, shown := players[1]["hand"]; shown {
+		t.Errorf("Expected p2's hand to be hidden from p1")
+	}
+	hand := players[0]["hand"].([]string)
+	expected := []string{"t8", "t4", "t6", "t5"}
+	for i := range expected {
+		if hand[i] != expected[i] {
+			t.Errorf("Expected hand %v, got %v", expected, hand)
+			break
+		}
+	}
+	if players[0]["next_card"] != "t3" {
+		t.Errorf("Expected t3 to be drawn next, got %v", players[0]["next_card"])
+	}
+
+	// The hand handed out does not change as play goes on
+	result, _ = engine.ProcessEnhancedAction("g1", "p1", game.EnhancedAction{Type: "spawn_troop", TroopID: "t8"})
+	if !result.Success || hand[0] != "t8" {
+		t.Errorf("Expected the earlier hand to keep t8, got %v", hand)
+	}
+
+	state, _ = engine.GetGameState("g1")
+	for _, player := range state["players"].([]map[string]interface{}) {
+		if _, shown := player["hand"]; shown {
+			t.Errorf("Expected spectators to see no hands")
+		}
+	}
+}
+
+func TestGameEngine_PlayerWithoutTroopsIsNotSeated(t *testing.T) {
+	engine := newTestEngine(t)
+	balance := *engine.Balance()
+	balance.Version = 2
+	balance.Troops = models.NewTroopCatalogue(nil)
+	engine.SetBalance(&balance)
+
+	gameObj, err := engine.CreateGame("g1", models.SimpleMode)
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	defer engine.CleanupGame("g1")
+
+	if err := engine.JoinGame("g1", models.NewPlayer("p1", "player1", "pass1")); err == nil {
+		t.Errorf("Expected a player without troops not to join")
+	}
+	if len(gameObj.Players) != 0 {
+		t.Errorf("Expected the seat to stay free, got %d players", len(gameObj.Players))
+	}
+}
@@ -0,0 +1,133 @@
+// tests/unit/draft_test.go - Draft mode pick and ban tests
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// startDraftGame creates a draft game over the nine deck test troops and
+// joins two players
+func startDraftGame(t *testing.T, draft config.DraftGameConfig) *game.GameEngine {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Simple:   config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30},
+			Enhanced: config.EnhancedGameConfig{CritMultiplier: 1.2},
+			Draft:    draft,
+		},
+		Troops: deckCatalogue(),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	if _, err := engine.CreateGame("draft1", models.DraftMode); err != nil {
+		t.Fatalf("Failed to create draft game: %v", err)
+	}
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		jsonStorage.SavePlayer(player)
+		if err := engine.JoinGame("draft1", player); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+	}
+	return engine
+}
+
+func draftAction(t *testing.T, engine *game.GameEngine, playerID, kind, troopID string) *game.DraftResult {
+	payload := json.RawMessage(fmt.Sprintf(`{"type":%q,"troop_id":%q}`, kind, troopID))
+	result, err := engine.ProcessAction("draft1", playerID, payload)
+	if err != nil {
+		t.Fatalf("Failed to %s %s: %v", kind, troopID, err)
+	}
+	return result.(*game.DraftResult)
+}
+
+func TestDraftMode_PicksBecomeTroops(t *testing.T) {
+	engine := startDraftGame(t, config.DraftGameConfig{BansPerPlayer: 1, PicksPerPlayer: 3})
+	defer engine.CleanupGame("draft1")
+
+	state, _ := engine.GetGameState("draft1")
+	if state["state"] != models.Drafting {
+		t.Fatalf("Expected the game to be drafting, got %v", state["state"])
+	}
+
+	if result := draftAction(t, engine, "p2", "ban", "t0"); result.Success {
+		t.Errorf("Expected p2 to wait for p1's ban")
+	}
+	if result := draftAction(t, engine, "p1", "pick", "t0"); result.Success {
+		t.Errorf("Expected p1 to have to ban first")
+	}
+	draftAction(t, engine, "p1", "ban", "t0")
+	draftAction(t, engine, "p2", "ban", "t1")
+	if result := draftAction(t, engine, "p1", "pick", "t1"); result.Success {
+		t.Errorf("Expected a banned troop not to be pickable")
+	}
+
+	picks := []string{"t2", "t3", "t4", "t5", "t6", "t7"}
+	var result *game.DraftResult
+	for i, troopID := range picks {
+		result = draftAction(t, engine, []string{"p1", "p2"}[i%2], "pick", troopID)
+		if !result.Success {
+			t.Fatalf("Expected pick %s to be accepted, got %s", troopID, result.Error)
+		}
+	}
+	if !result.Complete {
+		t.Fatalf("Expected the last pick to complete the draft")
+	}
+
+	gameObj, _ := engine.GetGame("draft1")
+	if gameObj.State != models.InProgress {
+		t.Fatalf("Expected the match to start, got %s", gameObj.State)
+	}
+	var troops []string
+	for _, troop := range gameObj.Players[0].AvailableTroops {
+		troops = append(troops, troop.ID)
+	}
+	if fmt.Sprint(troops) != "[t2 t4 t6]" {
+		t.Errorf("Expected p1 to play its picks, got %v", troops)
+	}
+
+	// The match is played as simple mode
+	attack, err := engine.ProcessAction("draft1", "p1", json.RawMessage(`{"type":"attack","troop_id":"t2","target_tower":0}`))
+	if err != nil || !attack.Accepted() {
+		t.Errorf("Expected p1 to attack with a drafted troop, got %+v (%v)", attack, err)
+	}
+}
+
+func TestDraftMode_PickTimerPicksForIdlePlayers(t *testing.T) {
+	engine := startDraftGame(t, config.DraftGameConfig{PicksPerPlayer: 1, PickTime: 1})
+	defer engine.CleanupGame("draft1")
+
+	complete := make(chan struct{})
+	engine.Events().Handle(game.EventDraftUpdated, func(event game.GameEventData) {
+		if event.Data.(map[string]interface{})["complete"] == true {
+			close(complete)
+		}
+	})
+
+	state, _ := engine.GetGameState("draft1")
+	if draft := state["draft"].(map[string]interface{}); draft["pick_remaining"] != 1 {
+		t.Errorf("Expected 1 second to pick, got %v", draft["pick_remaining"])
+	}
+
+	select {
+	case <-complete:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected the pick timer to finish the draft")
+	}
+
+	gameObj, _ := engine.GetGame("draft1")
+	if gameObj.Players[0].AvailableTroops[0].ID != "t0" || gameObj.Players[1].AvailableTroops[0].ID != "t1" {
+		t.Errorf("Expected idle players to get the first troops left in the pool")
+	}
+}
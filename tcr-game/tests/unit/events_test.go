@@ -0,0 +1,263 @@
+// tests/unit/events_test.go - Game engine event publishing tests
+package unit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+// recordEvents collects the engine's events of the given types in the order they were published
+func recordEvents(engine *game.GameEngine, eventTypes ...game.EventType) func() []game.GameEventData {
+	var events []game.GameEventData
+	var mutex sync.Mutex
+	for _, eventType := range eventTypes {
+		engine.Events().Handle(eventType, func(event game.GameEventData) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			events = append(events, event)
+		})
+	}
+
+	return func() []game.GameEventData {
+		mutex.Lock()
+		defer mutex.Unlock()
+		recorded := events
+		events = nil
+		return recorded
+	}
+}
+
+func eventTypesOf(events []game.GameEventData) []game.EventType {
+	types := make([]game.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestGameEngine_PublishesJoinsAndStart(t *testing.T) {
+	engine := newTestEngine(t)
+	events := recordEvents(engine, game.EventPlayerJoined, game.EventGameStarted)
+
+	engine.CreateGame("g1", models.SimpleMode)
+	defer engine.CleanupGame("g1")
+	for _, id := range []string{"p1", "p2"} {
+		if err := engine.JoinGame("g1", models.NewPlayer(id, id, "pass")); err != nil {
+			t.Fatalf("Failed to join %s: %v", id, err)
+		}
+	}
+
+	recorded := events()
+	expected := []game.EventType{game.EventPlayerJoined, game.EventPlayerJoined, game.EventGameStarted}
+	if len(recorded) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, eventTypesOf(recorded))
+	}
+	for i := range expected {
+		if recorded[i].Type != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, eventTypesOf(recorded))
+			break
+		}
+	}
+
+	joined := recorded[1].Data.(map[string]interface{})
+	if joined["player_id"] != "p2" || joined["team"] != 1 {
+		t.Errorf("Expected p2 to join on side 1, got %v", joined)
+	}
+	started := recorded[2].Data.(map[string]interface{})
+	if started["state"] != models.InProgress || started["players"] != 2 {
+		t.Errorf("Expected the game to start with 2 players, got %v", started)
+	}
+}
+
+func TestGameEngine_PublishesActions(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.CreateGame("g1", models.SimpleMode)
+	defer engine.CleanupGame("g1")
+	for _, id := range []string{"p1", "p2"} {
+		engine.JoinGame("g1", models.NewPlayer(id, id, "pass"))
+	}
+	events := recordEvents(engine, game.EventActionResult, game.EventAttackMade, game.EventTowerDestroyed)
+
+	gameObj, _ := engine.GetGame("g1")
+	attack := func(playerID string) {
+		troopID := gameObj.Players[gameObj.CurrentTurn].AvailableTroops[0].ID
+		payload, _ := json.Marshal(game.TurnAction{Type: "attack", TroopID: troopID, TargetTower: 0})
+		if _, err := engine.ProcessAction("g1", playerID, payload); err != nil {
+			t.Fatalf("Failed to process action: %v", err)
+		}
+	}
+
+	// Rejected actions are published without an attack
+	attack("p2")
+	recorded := events()
+	if len(recorded) != 1 || recorded[0].Type != game.EventActionResult {
+		t.Fatalf("Expected only the rejected result, got %v", eventTypesOf(recorded))
+	}
+	if data := recorded[0].Data.(map[string]interface{}); data["player_id"] != "p2" || data["result"].(game.ActionResult).Accepted() {
+		t.Errorf("Expected p2's rejected result, got %v", data)
+	}
+
+	gameObj.Players[1].Towers[0].HP = 1
+	attack("p1")
+	recorded = events()
+	expected := []game.EventType{game.EventActionResult, game.EventAttackMade, game.EventTowerDestroyed}
+	if len(recorded) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, eventTypesOf(recorded))
+	}
+	if battle := recorded[1].Data.(*game.BattleResult); battle.AttackerID != "p1" || !battle.TowerDestroyed {
+		t.Errorf("Expected p1's attack to destroy the tower, got %+v", battle)
+	}
+	if destroyed := recorded[2].Data.(map[string]interface{}); destroyed["player_id"] != "p2" || destroyed["tower_index"] != 0 {
+		t.Errorf("Expected p2's first tower to fall, got %v", destroyed)
+	}
+}
+
+func TestGameEngine_PublishesActionsBeforeTheTurnOrGameEnd(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.CreateGame("g1", models.SimpleMode)
+	defer engine.CleanupGame("g1")
+	for _, id := range []string{"p1", "p2"} {
+		engine.JoinGame("g1", models.NewPlayer(id, id, "pass"))
+	}
+	events := recordEvents(engine, game.EventActionResult, game.EventAttackMade, game.EventTowerDestroyed, game.EventTurnChanged, game.EventGameEnded)
+
+	gameObj, _ := engine.GetGame("g1")
+	attack := func(playerID string, tower int) []game.EventType {
+		troopID := gameObj.Players[gameObj.CurrentTurn].AvailableTroops[0].ID
+		payload, _ := json.Marshal(game.TurnAction{Type: "attack", TroopID: troopID, TargetTower: tower})
+		if _, err := engine.ProcessAction("g1", playerID, payload); err != nil {
+			t.Fatalf("Failed to process action: %v", err)
+		}
+		recorded := events()
+		for i := 1; i < len(recorded); i++ {
+			if recorded[i].Seq <= recorded[i-1].Seq {
+				t.Fatalf("Expected events in seq order, got %v", recorded)
+			}
+		}
+		return eventTypesOf(recorded)
+	}
+
+	defender := gameObj.Players[1]
+	defender.Towers[0].HP = 1
+	expected := []game.EventType{game.EventActionResult, game.EventAttackMade, game.EventTowerDestroyed, game.EventTurnChanged}
+	if recorded := attack("p1", 0); !reflect.DeepEqual(recorded, expected) {
+		t.Fatalf("Expected the tower kill before the turn change, got %v", recorded)
+	}
+
+	attack("p2", 0)
+	defender.Towers[1].HP = 0
+	defender.Towers[2].HP = 1
+	expected = []game.EventType{game.EventActionResult, game.EventAttackMade, game.EventTowerDestroyed, game.EventGameEnded}
+	if recorded := attack("p1", 2); !reflect.DeepEqual(recorded, expected) {
+		t.Errorf("Expected the king kill before game_ended, got %v", recorded)
+	}
+}
+
+func TestGameEngine_PublishesManaSpent(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.CreateGame("g1", models.EnhancedMode)
+	defer engine.CleanupGame("g1")
+	for _, id := range []string{"p1", "p2"} {
+		engine.JoinGame("g1", models.NewPlayer(id, id, "pass"))
+	}
+	events := recordEvents(engine, game.EventManaUpdated)
+
+	result, err := engine.ProcessEnhancedAction("g1", "p1", game.EnhancedAction{Type: "spawn_troop", TroopID: "goblin"})
+	if err != nil || !result.Success {
+		t.Fatalf("Expected the goblin to be spawned, got %+v (%v)", result, err)
+	}
+
+	for _, event := range events() {
+		data := event.Data.(map[string]interface{})
+		if data["player_id"] == "p1" && data["mana"] == result.PlayerMana {
+			return
+		}
+	}
+	t.Errorf("Expected p1's mana after spawning to be published")
+}
+
+func TestEventManager_NumbersEventsPerGame(t *testing.T) {
+	events := game.NewEventManager()
+	var seqs []string
+	events.Handle(game.EventArenaTick, func(event game.GameEventData) {
+		seqs = append(seqs, event.GameID+":"+strconv.FormatInt(event.Seq, 10))
+	})
+
+	for _, gameID := range []string{"g1", "g2", "g1"} {
+		events.Publish(game.GameEventData{Type: game.EventArenaTick, GameID: gameID})
+	}
+
+	expected := []string{"g1:1", "g2:1", "g1:2"}
+	if strings.Join(seqs, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected events numbered %v, got %v", expected, seqs)
+	}
+	if last := events.LastSeq("g1"); last != 2 {
+		t.Errorf("Expected g1's last seq to be 2, got %d", last)
+	}
+}
+
+func TestEventManager_KeepsRecentEvents(t *testing.T) {
+	events := game.NewEventManager()
+	events.SetHistorySize(3)
+	for i := 0; i < 5; i++ {
+		events.Publish(game.GameEventData{Type: game.EventArenaTick, GameID: "g1"})
+	}
+
+	missed, kept := events.Since("g1", 2)
+	if !kept || len(missed) != 3 || missed[0].Seq != 3 || missed[2].Seq != 5 {
+		t.Errorf("Expected events 3 to 5 after seq 2, got %v (%v)", missed, kept)
+	}
+	if missed, kept := events.Since("g1", 5); !kept || len(missed) != 0 {
+		t.Errorf("Expected nothing missed after the latest event, got %v (%v)", missed, kept)
+	}
+	if _, kept := events.Since("g1", 1); kept {
+		t.Errorf("Expected events older than the history to be reported as lost")
+	}
+	if _, kept := events.Since("g1", 6); kept {
+		t.Errorf("Expected a seq the game has not reached to be rejected")
+	}
+
+	events.CleanupGame("g1")
+	if last := events.LastSeq("g1"); last != 0 {
+		t.Errorf("Expected the game's events to be forgotten, got last seq %d", last)
+	}
+}
+
+func TestEventManager_HandlesEachGamesEventsInSeqOrder(t *testing.T) {
+	events := game.NewEventManager()
+	var seqs []int64
+	events.Handle(game.EventArenaTick, func(event game.GameEventData) {
+		// A slow handler gives later events every chance to overtake
+		time.Sleep(time.Millisecond)
+		seqs = append(seqs, event.Seq)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				events.Publish(game.GameEventData{Type: game.EventArenaTick, GameID: "g1"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seqs) != 80 {
+		t.Fatalf("Expected 80 events handled, got %d", len(seqs))
+	}
+	for i, seq := range seqs {
+		if seq != int64(i+1) {
+			t.Fatalf("Expected event %d to be handled in position %d, got %v", seq, i+1, seqs)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// tests/unit/ffa_test.go - Free-for-all elimination and placing tests
+package unit
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// startFFAGame creates a free-for-all game for p1, p2 and p3 and records
+// every elimination published
+func startFFAGame(t *testing.T) (*game.GameEngine, func() []map[string]interface{}) {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Simple:   config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30},
+			Enhanced: config.EnhancedGameConfig{CritMultiplier: 1.2},
+			FFA:      config.FFAGameConfig{ExpWin: 40, ExpDraw: 10},
+		},
+		Troops: deckCatalogue(),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	var eliminated []map[string]interface{}
+	var mutex sync.Mutex
+	engine.Events().Handle(game.EventPlayerEliminated, func(event game.GameEventData) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		eliminated = append(eliminated, event.Data.(map[string]interface{}))
+	})
+
+	if _, err := engine.CreateGameWithPlayers("ffa1", models.FFAMode, 3); err != nil {
+		t.Fatalf("Failed to create free-for-all game: %v", err)
+	}
+	for _, id := range []string{"p1", "p2", "p3"} {
+		player := models.NewPlayer(id, id, "pass")
+		jsonStorage.SavePlayer(player)
+		if err := engine.JoinGame("ffa1", player); err != nil {
+			t.Fatalf("Failed to join %s: %v", id, err)
+		}
+	}
+
+	return engine, func() []map[string]interface{} {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]map[string]interface{}(nil), eliminated...)
+	}
+}
+
+// newFFAGame builds a started three-player free-for-all game without an engine
+func newFFAGame() *models.Game {
+	gameObj := models.NewGame("ffa_battle", models.FFAMode)
+	gameObj.MaxPlayers = 3
+	for _, id := range []string{"p1", "p2", "p3"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{models.NewTroop("knight", "Knight", 100, 30, 5, 0, 1, "")}
+		gameObj.AddPlayer(player)
+	}
+	gameObj.Start()
+	return gameObj
+}
+
+// weaken leaves a player's king tower on 1 HP with its guards destroyed
+func weaken(player *models.Player) {
+	for _, tower := range player.Towers {
+		tower.HP = 0
+	}
+	player.KingTower().HP = 1
+}
+
+func TestFFAMode_Seats(t *testing.T) {
+	engine, _ := startFFAGame(t)
+	defer engine.CleanupGame("ffa1")
+
+	gameObj, _ := engine.GetGame("ffa1")
+	if gameObj.State != models.InProgress {
+		t.Fatalf("Expected the game to start with three players, got %s", gameObj.State)
+	}
+	for _, player := range gameObj.Players {
+		if len(player.Towers) != 3 {
+			t.Errorf("Expected %s to have a full set of towers, got %d", player.ID, len(player.Towers))
+		}
+	}
+
+	for _, players := range []int{2, 5} {
+		if _, err := engine.CreateGameWithPlayers("ffa_bad", models.FFAMode, players); err == nil {
+			t.Errorf("Expected a %d-player free-for-all to be rejected", players)
+		}
+	}
+	if _, err := engine.CreateGameWithPlayers("simple_bad", models.SimpleMode, 3); err == nil {
+		t.Errorf("Expected a 3-player simple game to be rejected")
+	}
+	if gameObj, err := engine.CreateGame("ffa4", models.FFAMode); err != nil || gameObj.Capacity() != 4 {
+		t.Errorf("Expected a free-for-all to seat 4 by default, got %v (%v)", gameObj, err)
+	}
+}
+
+func TestFFAMode_LastPlayerStandingWins(t *testing.T) {
+	engine := game.NewBattleEngine(1.2)
+	gameObj := newFFAGame()
+	weaken(gameObj.Players[2])
+
+	result, err := engine.ExecuteAttack(gameObj, "p1", "p3", "knight", 2)
+	if err != nil {
+		t.Fatalf("Failed to attack: %v", err)
+	}
+	if result.GameEnded {
+		t.Fatalf("Expected the game to go on with two players standing")
+	}
+	if len(result.Eliminated) != 1 || result.Eliminated[0] != "p3" || gameObj.PlaceOf("p3") != 3 {
+		t.Errorf("Expected p3 to be knocked out in third, got %v and place %d", result.Eliminated, gameObj.PlaceOf("p3"))
+	}
+
+	if _, err := engine.ExecuteAttack(gameObj, "p2", "p3", "knight", 2); err == nil {
+		t.Errorf("Expected an eliminated player not to be targetable")
+	}
+	if _, err := engine.ExecuteAttack(gameObj, "p2", "", "knight", 0); err != nil {
+		t.Errorf("Expected the last opponent standing to be targeted by default, got %v", err)
+	}
+
+	weaken(gameObj.Players[0])
+	result, err = engine.ExecuteAttack(gameObj, "p2", "p1", "knight", 2)
+	if err != nil {
+		t.Fatalf("Failed to attack: %v", err)
+	}
+	if !result.GameEnded || result.Winner != "p2" {
+		t.Errorf("Expected p2 to win as the last player standing, got %+v", result)
+	}
+
+	gameObj.Winner = gameObj.Players[1]
+	engine.RecordPlaces(gameObj)
+	for id, want := range map[string]int{"p1": 2, "p2": 1, "p3": 3} {
+		if place := gameObj.PlaceOf(id); place != want {
+			t.Errorf("Expected %s to finish %d, got %d", id, want, place)
+		}
+	}
+}
+
+func TestFFAMode_RecordPlacesOnTowersLost(t *testing.T) {
+	engine := game.NewBattleEngine(1.2)
+	gameObj := newFFAGame()
+	gameObj.Players[0].Towers[0].HP = 0
+
+	engine.RecordPlaces(gameObj)
+	for id, want := range map[string]int{"p1": 3, "p2": 1, "p3": 1} {
+		if place := gameObj.PlaceOf(id); place != want {
+			t.Errorf("Expected %s to finish %d, got %d", id, want, place)
+		}
+	}
+}
+
+func TestFFAMode_TurnsSkipEliminatedPlayers(t *testing.T) {
+	engine, eliminated := startFFAGame(t)
+	defer engine.CleanupGame("ffa1")
+
+	gameObj, _ := engine.GetGame("ffa1")
+	weaken(gameObj.Players[1])
+
+	attack := func(playerID, target string, tower int) *game.TurnResult {
+		troopID := gameObj.Players[gameObj.CurrentTurn].AvailableTroops[0].ID
+		payload, _ := json.Marshal(game.TurnAction{Type: "attack", TroopID: troopID, TargetPlayer: target, TargetTower: tower})
+		result, err := engine.ProcessAction("ffa1", playerID, payload)
+		if err != nil || !result.Accepted() {
+			t.Fatalf("Expected %s's attack on %s to be accepted, got %+v (%v)", playerID, target, result, err)
+		}
+		return result.(*game.TurnResult)
+	}
+
+	if result := attack("p1", "p2", 2); result.GameEnded || result.NextPlayer != "p1" {
+		t.Fatalf("Expected p1 to knock p2 out and go again, got %+v", result)
+	}
+	if events := eliminated(); len(events) != 1 || events[0]["player_id"] != "p2" || events[0]["place"] != 3 {
+		t.Errorf("Expected p2's elimination in third to be published, got %v", events)
+	}
+
+	if result := attack("p1", "p3", 0); result.NextPlayer != "p3" {
+		t.Errorf("Expected p2 to be skipped, got %s next", result.NextPlayer)
+	}
+
+	state, _ := engine.GetGameState("ffa1")
+	if targets := state["current_player"].(map[string]interface{})["valid_targets"]; len(targets.([]int)) != 1 {
+		t.Errorf("Expected p3's only target to be p1, got %v", targets)
+	}
+
+	gameObj.Players[2].Towers[0].HP = 0
+	if err := engine.EndGame("ffa1", "test"); err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+	rewards := awaitRewards(t, engine, "ffa1")
+	if len(rewards) != 3 || rewards["p2"].Place != 3 || rewards["p2"].Result != models.ResultLoss {
+		t.Fatalf("Expected every player to be rewarded by place, got %v", rewards)
+	}
+	if rewards["p1"].Place != 1 || rewards["p1"].Result != models.ResultWin || rewards["p1"].Experience != 40 {
+		t.Errorf("Expected p1 to win on towers lost, got %+v", rewards["p1"])
+	}
+}
+
+func TestFFAMode_ForfeitKnocksOutThePlayer(t *testing.T) {
+	manager := game.NewSimpleGameManager(4, 1, 1, 1.2)
+	events := game.NewEventManager()
+	manager.SetEventManager(events)
+	defer manager.CleanupGame("ffa_timed")
+
+	eliminated := make(chan string, 1)
+	events.Handle(game.EventPlayerEliminated, func(event game.GameEventData) {
+		eliminated <- event.Data.(map[string]interface{})["player_id"].(string)
+	})
+
+	gameObj := models.NewGameWithSeed("ffa_timed", models.FFAMode, 3)
+	gameObj.MaxPlayers = 3
+	for _, id := range []string{"p1", "p2", "p3"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{models.NewTroop("goblin", "Goblin", 100, 30, 5, 0, 2, "")}
+		gameObj.AddPlayer(player)
+	}
+	if err := manager.StartGame(gameObj); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	select {
+	case playerID := <-eliminated:
+		if playerID != "p1" {
+			t.Errorf("Expected p1 to forfeit, got %s", playerID)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected the idle player to forfeit")
+	}
+
+	state := manager.GetGameState(gameObj)
+	if state["state"] != models.InProgress || state["current_turn"] != 1 {
+		t.Errorf("Expected the game to go on with p2's turn, got %v on turn %v", state["state"], state["current_turn"])
+	}
+	if gameObj.PlaceOf("p1") != 3 {
+		t.Errorf("Expected p1 to finish third, got %d", gameObj.PlaceOf("p1"))
+	}
+}
+
+func TestRatingSystem_RateFreeForAll(t *testing.T) {
+	ratings := game.NewRatingSystem(config.RatingConfig{KFactor: 32})
+	players := []*models.Player{
+		models.NewPlayer("p1", "p1", ""),
+		models.NewPlayer("p2", "p2", ""),
+		models.NewPlayer("p3", "p3", ""),
+	}
+
+	changes, err := ratings.RateFreeForAll("g1", models.FFAMode, map[string]int{"p1": 1, "p2": 2, "p3": 3}, players, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to rate free-for-all game: %v", err)
+	}
+	expected := map[string]struct {
+		after  int
+		result models.MatchResult
+	}{
+		"p1": {1216, models.ResultWin},
+		"p2": {1200, models.ResultLoss},
+		"p3": {1184, models.ResultLoss},
+	}
+	for id, want := range expected {
+		if change := changes[id]; change.After != want.after || change.Result != want.result {
+			t.Errorf("Expected %s to finish on %d with a %s, got %+v", id, want.after, want.result, change)
+		}
+	}
+
+	if _, err := ratings.RateFreeForAll("g2", models.FFAMode, map[string]int{"p1": 1, "p2": 2}, players, time.Now()); err == nil {
+		t.Errorf("Expected a player without a place to be rejected")
+	}
+}
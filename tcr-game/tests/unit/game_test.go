@@ -24,7 +24,7 @@ func TestBattleEngine_CalculateDamage(t *testing.T) {
 		MaxHP:   100,
 	}
 	
-	damage, crit := engine.CalculateDamage(troop, tower, false)
+	damage, crit := engine.CalculateDamage(troop, tower, false, models.NewGameRNG(1))
 	
 	if damage != 20 {
 		t.Errorf("Expected damage 20, got %d", damage)
@@ -36,7 +36,7 @@ func TestBattleEngine_CalculateDamage(t *testing.T) {
 }
 
 func TestSimpleGameManager_StartGame(t *testing.T) {
-	manager := game.NewSimpleGameManager(2, 30, 1.2)
+	manager := game.NewSimpleGameManager(2, 30, 0, 1.2)
 	gameObj := models.NewGame("test_game", models.SimpleMode)
 	
 	// Add two players
@@ -62,4 +62,44 @@ func TestSimpleGameManager_StartGame(t *testing.T) {
 	if gameObj.State != models.InProgress {
 		t.Errorf("Expected game state to be InProgress, got %v", gameObj.State)
 	}
-}
\ No newline at end of file
+}
+
+func TestSimpleGameManager_StartGameIsDeterministic(t *testing.T) {
+	dealt := func() []string {
+		manager := game.NewSimpleGameManager(2, 30, 0, 1.2)
+		gameObj := models.NewGameWithSeed("seeded_game", models.SimpleMode, 42)
+		
+		for _, id := range []string{"p1", "p2"} {
+			player := models.NewPlayer(id, id, "pass")
+			for _, troopID := range []string{"goblin", "archer", "knight", "wizard", "dragon"} {
+				player.AvailableTroops = append(player.AvailableTroops, &models.Troop{ID: troopID, HP: 100, MaxHP: 100})
+			}
+			gameObj.AddPlayer(player)
+		}
+		
+		if err := manager.StartGame(gameObj); err != nil {
+			t.Fatalf("Failed to start game: %v", err)
+		}
+		
+		ids := []string{}
+		for _, player := range gameObj.Players {
+			for _, troop := range player.AvailableTroops {
+				ids = append(ids, troop.ID)
+			}
+		}
+		return ids
+	}
+	
+	first := dealt()
+	second := dealt()
+	
+	if len(first) != 6 {
+		t.Fatalf("Expected 6 troops dealt, got %d", len(first))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Expected identical deal for the same seed, got %v and %v", first, second)
+			break
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// tests/unit/leaderboard_test.go - Leaderboard index tests
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func savePlayerWith(t *testing.T, jsonStorage *storage.JSONStorage, id string, rating, wins int) *models.Player {
+	player := models.NewPlayer(id, id, "pass")
+	player.RatingFor(models.SimpleMode).Value = rating
+	player.Stats.GamesWon = wins
+	if err := jsonStorage.SavePlayer(player); err != nil {
+		t.Fatalf("Failed to save %s: %v", id, err)
+	}
+	return player
+}
+
+func TestLeaderboard_RanksTiesAndPages(t *testing.T) {
+	jsonStorage := storage.NewJSONStorage(t.TempDir(), "", "", "")
+	savePlayerWith(t, jsonStorage, "alice", 1300, 1)
+	savePlayerWith(t, jsonStorage, "bob", 1250, 5)
+	savePlayerWith(t, jsonStorage, "carol", 1250, 3)
+	savePlayerWith(t, jsonStorage, "dave", 1100, 0)
+
+	page, err := jsonStorage.Leaderboard().Page(models.SimpleMode, storage.SortByRating, 1, 3, "dave")
+	if err != nil {
+		t.Fatalf("Failed to get leaderboard: %v", err)
+	}
+
+	expected := []struct {
+		id   string
+		rank int
+	}{{"alice", 1}, {"bob", 2}, {"carol", 2}}
+	if len(page.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(page.Entries))
+	}
+	for i, want := range expected {
+		if page.Entries[i].PlayerID != want.id || page.Entries[i].Rank != want.rank {
+			t.Errorf("Expected %s at rank %d, got %s at rank %d", want.id, want.rank, page.Entries[i].PlayerID, page.Entries[i].Rank)
+		}
+	}
+	if page.TotalPages != 2 {
+		t.Errorf("Expected 2 pages, got %d", page.TotalPages)
+	}
+	if page.Own == nil || page.Own.Rank != 4 {
+		t.Errorf("Expected dave's own rank to be 4, got %+v", page.Own)
+	}
+
+	// Saving a player moves them in every view
+	savePlayerWith(t, jsonStorage, "dave", 1400, 9)
+	wins, _ := jsonStorage.Leaderboard().Page("", storage.SortByWins, 1, 10, "dave")
+	if wins.Entries[0].PlayerID != "dave" || wins.TotalPlayers != 4 {
+		t.Errorf("Expected dave to lead by wins among 4 players, got %+v", wins.Entries[0])
+	}
+
+	if _, err := jsonStorage.Leaderboard().Page("", "fastest", 1, 10, ""); err == nil {
+		t.Errorf("Expected an unknown sort to be rejected")
+	}
+}
+
+func TestJSONStorage_LoadLeaderboardIndexesStoredPlayers(t *testing.T) {
+	dir := t.TempDir()
+	savePlayerWith(t, storage.NewJSONStorage(dir, "", "", ""), "alice", 1300, 1)
+	savePlayerWith(t, storage.NewJSONStorage(dir, "", "", ""), "bob", 1350, 2)
+
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	if err := jsonStorage.LoadLeaderboard(); err != nil {
+		t.Fatalf("Failed to load leaderboard: %v", err)
+	}
+
+	page, _ := jsonStorage.Leaderboard().Page(models.SimpleMode, storage.SortByRating, 1, 10, "alice")
+	if page.TotalPlayers != 2 || page.Entries[0].PlayerID != "bob" || page.Own.Rank != 2 {
+		t.Errorf("Expected bob first and alice second, got %+v", page.Entries)
+	}
+}
+
+func TestJSONStorage_LoadLeaderboardSkipsUnreadableRecords(t *testing.T) {
+	dir := t.TempDir()
+	savePlayerWith(t, storage.NewJSONStorage(dir, "", "", ""), "alice", 1300, 1)
+	for name, contents := range map[string]string{"empty.json": "", "corrupt.json": "{\"id\": "} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	if err := jsonStorage.LoadLeaderboard(); err != nil {
+		t.Fatalf("Expected unreadable records to be skipped, got %v", err)
+	}
+	page, _ := jsonStorage.Leaderboard().Page(models.SimpleMode, storage.SortByRating, 1, 10, "")
+	if page.TotalPlayers != 1 || page.Entries[0].PlayerID != "alice" {
+		t.Errorf("Expected only alice to be indexed, got %+v", page.Entries)
+	}
+}
+
+func TestJSONStorage_FailedSaveRestoresRecords(t *testing.T) {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	alice := savePlayerWith(t, jsonStorage, "alice", 1300, 1)
+
+	// bob's record cannot be replaced, so the save fails after alice's
+	if err := os.MkdirAll(filepath.Join(dir, "bob.json", "locked"), 0755); err != nil {
+		t.Fatalf("Failed to block bob's record: %v", err)
+	}
+	carol := models.NewPlayer("carol", "carol", "pass")
+	alice.Stats.GamesWon = 2
+	if err := jsonStorage.SavePlayers(carol, alice, models.NewPlayer("bob", "bob", "pass")); err == nil {
+		t.Fatalf("Expected the save to fail")
+	}
+
+	if _, err := jsonStorage.LoadPlayer("carol"); err == nil {
+		t.Errorf("Expected carol's new record to be removed")
+	}
+	stored, err := jsonStorage.LoadPlayer("alice")
+	if err != nil || stored.Stats.GamesWon != 1 {
+		t.Errorf("Expected alice's previous record to be restored, got %+v %v", stored, err)
+	}
+}
+
+func TestLeaderboard_RanksEveryRatedMode(t *testing.T) {
+	jsonStorage := storage.NewJSONStorage(t.TempDir(), "", "", "")
+	alice := models.NewPlayer("alice", "alice", "pass")
+	alice.RatingFor(models.FFAMode).Value = 1400
+	jsonStorage.SavePlayer(alice)
+	savePlayerWith(t, jsonStorage, "bob", 1300, 0)
+
+	page, err := jsonStorage.Leaderboard().Page(models.FFAMode, storage.SortByRating, 1, 10, "bob")
+	if err != nil {
+		t.Fatalf("Expected a mode with stored ratings to be ranked: %v", err)
+	}
+	if page.TotalPlayers != 2 || page.Entries[0].PlayerID != "alice" || page.Own.Rating != models.DefaultRating {
+		t.Errorf("Expected alice first and bob at the default rating, got %+v", page.Entries)
+	}
+
+	if _, err := jsonStorage.Leaderboard().Page(models.DraftMode, storage.SortByRating, 1, 10, ""); err == nil {
+		t.Errorf("Expected a mode nobody is rated in to be rejected until added")
+	}
+	jsonStorage.Leaderboard().AddModes(models.DraftMode)
+	if page, err := jsonStorage.Leaderboard().Page(models.DraftMode, storage.SortByRating, 1, 10, ""); err != nil || page.TotalPlayers != 2 {
+		t.Errorf("Expected every player ranked in an added mode, got %+v (%v)", page, err)
+	}
+}
@@ -0,0 +1,110 @@
+// tests/unit/matchmaking_test.go - Matchmaking queue tests
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func newTestEngine(t *testing.T) *game.GameEngine {
+	dir := t.TempDir()
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Simple:   config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30},
+			Enhanced: config.EnhancedGameConfig{GameDuration: 180, ManaRegen: 1.0, CritMultiplier: 1.2, ExpWin: 30, ExpDraw: 10},
+		},
+		Troops: models.NewTroopCatalogue([]*models.Troop{
+			models.NewTroop("goblin", "Goblin", 100, 30, 5, 0.1, 2, ""),
+			models.NewTroop("archer", "Archer", 80, 40, 3, 0.15, 3, ""),
+			models.NewTroop("knight", "Knight", 150, 35, 10, 0.05, 4, ""),
+		}),
+	}
+	engine := game.NewGameEngine(&config.Config{}, storage.NewJSONStorage(dir, "", "", ""), storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+	return engine
+}
+
+func newRatedPlayer(id string, rating int) *models.Player {
+	player := models.NewPlayer(id, id, "pass")
+	player.RatingFor(models.SimpleMode).Value = rating
+	player.RatingFor(models.EnhancedMode).Value = rating
+	return player
+}
+
+func TestMatchmaker_PairsClosestPlayers(t *testing.T) {
+	engine := newTestEngine(t)
+	matchmaker := game.NewMatchmaker(engine, config.MatchmakingConfig{InitialWindow: 50, WindowGrowth: 10, MaxWindow: 400})
+	now := time.Now()
+
+	for _, player := range []*models.Player{newRatedPlayer("p1", 1200), newRatedPlayer("p2", 1240), newRatedPlayer("p3", 1210)} {
+		if _, err := matchmaker.Enqueue(player, models.SimpleMode, now); err != nil {
+			t.Fatalf("Failed to enqueue %s: %v", player.ID, err)
+		}
+	}
+
+	matches := matchmaker.MatchPlayers(now)
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Players[0].ID != "p1" || matches[0].Players[1].ID != "p3" {
+		t.Errorf("Expected p1 to be paired with p3, got %s and %s", matches[0].Players[0].ID, matches[0].Players[1].ID)
+	}
+
+	gameObj, err := engine.GetGame(matches[0].GameID)
+	if err != nil || len(gameObj.Players) != 2 || gameObj.State != models.InProgress {
+		t.Errorf("Expected a started game with both players, got %v", err)
+	}
+
+	if status := matchmaker.Status("p1", now); status.State != game.QueueMatched || status.GameID != matches[0].GameID {
+		t.Errorf("Expected p1 to be matched into %s, got %+v", matches[0].GameID, status)
+	}
+	if status := matchmaker.Status("p2", now); status.State != game.QueueWaiting || status.Position != 1 {
+		t.Errorf("Expected p2 to be first in line, got %+v", status)
+	}
+}
+
+func TestMatchmaker_WindowWidensWithWaitTime(t *testing.T) {
+	engine := newTestEngine(t)
+	matchmaker := game.NewMatchmaker(engine, config.MatchmakingConfig{InitialWindow: 10, WindowGrowth: 10, MaxWindow: 100})
+	now := time.Now()
+
+	matchmaker.Enqueue(newRatedPlayer("novice", 1200), models.EnhancedMode, now)
+	matchmaker.Enqueue(newRatedPlayer("veteran", 1270), models.EnhancedMode, now.Add(2*time.Second))
+
+	if matches := matchmaker.MatchPlayers(now.Add(5 * time.Second)); len(matches) != 0 {
+		t.Fatalf("Expected no match while the window is %d", matchmaker.Status("novice", now.Add(5*time.Second)).Window)
+	}
+
+	matches := matchmaker.MatchPlayers(now.Add(6 * time.Second))
+	if len(matches) != 1 {
+		t.Fatalf("Expected a match once the window reached 70")
+	}
+	engine.CleanupGame(matches[0].GameID)
+}
+
+func TestMatchmaker_Cancel(t *testing.T) {
+	matchmaker := game.NewMatchmaker(newTestEngine(t), config.MatchmakingConfig{InitialWindow: 100})
+	now := time.Now()
+
+	player := newRatedPlayer("p1", 1200)
+	matchmaker.Enqueue(player, models.SimpleMode, now)
+	if _, err := matchmaker.Enqueue(player, models.SimpleMode, now); err == nil {
+		t.Errorf("Expected a second enqueue to fail")
+	}
+
+	if err := matchmaker.Cancel("p1"); err != nil {
+		t.Fatalf("Failed to cancel: %v", err)
+	}
+	if status := matchmaker.Status("p1", now); status.State != game.QueueIdle {
+		t.Errorf("Expected idle after cancel, got %s", status.State)
+	}
+	if err := matchmaker.Cancel("p1"); err == nil {
+		t.Errorf("Expected cancelling twice to fail")
+	}
+}
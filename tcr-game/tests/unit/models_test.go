@@ -59,4 +59,65 @@ func TestTower_TakeDamage(t *testing.T) {
 	if tower.IsAlive() {
 		t.Errorf("Expected tower to be dead")
 	}
-}
\ No newline at end of file
+}
+
+func TestPlayer_InitializeTowersFromLayout(t *testing.T) {
+	catalogue := models.DefaultTowerCatalogue()
+	catalogue.Layout = []models.TowerPlacement{
+		{Type: models.GuardTower, Name: "Lone Guard", Position: 1},
+		{Type: models.KingTower, Position: 2},
+	}
+	if err := catalogue.Validate(); err != nil {
+		t.Fatalf("Expected layout to be valid, got %v", err)
+	}
+	
+	player := models.NewPlayer("test", "testuser", "pass")
+	player.InitializeTowers(catalogue)
+	
+	if len(player.Towers) != 2 {
+		t.Fatalf("Expected 2 towers, got %d", len(player.Towers))
+	}
+	if player.Towers[0].Name != "Lone Guard" || player.Towers[0].Position != 1 {
+		t.Errorf("Expected lone guard on lane 1, got %+v", player.Towers[0])
+	}
+	if king := player.KingTower(); king == nil || king.Name != "King Tower" || king.HP != 500 {
+		t.Errorf("Expected king tower from spec, got %+v", king)
+	}
+}
+
+func TestTowerCatalogue_Validate(t *testing.T) {
+	noKing := models.DefaultTowerCatalogue()
+	noKing.Layout = noKing.Layout[:2]
+	if err := noKing.Validate(); err == nil {
+		t.Errorf("Expected layout without a king to be rejected")
+	}
+	
+	kingFirst := models.DefaultTowerCatalogue()
+	kingFirst.Layout[0], kingFirst.Layout[2] = kingFirst.Layout[2], kingFirst.Layout[0]
+	if err := kingFirst.Validate(); err == nil {
+		t.Errorf("Expected king before guards to be rejected")
+	}
+	
+	badSpec := models.DefaultTowerCatalogue()
+	badSpec.Specs[models.GuardTower] = models.TowerSpec{Name: "Guard", HP: 0}
+	if err := badSpec.Validate(); err == nil {
+		t.Errorf("Expected zero hp to be rejected")
+	}
+	
+	offLane := models.DefaultTowerCatalogue()
+	offLane.Layout[1].Position = models.ArenaLanes
+	if err := offLane.Validate(); err == nil {
+		t.Errorf("Expected a guard tower off the arena's lanes to be rejected")
+	}
+	
+	cannon := models.DefaultTowerCatalogue()
+	cannon.Specs["cannon"] = models.TowerSpec{Name: "Cannon", HP: 200, Attack: 30}
+	cannon.Layout[1].Type = "cannon"
+	if err := cannon.Validate(); err != nil {
+		t.Errorf("Expected a tower type with a spec to be allowed, got %v", err)
+	}
+	cannon.Layout[1].Position = -1
+	if err := cannon.Validate(); err == nil {
+		t.Errorf("Expected a cannon off the arena's lanes to be rejected")
+	}
+}
@@ -0,0 +1,108 @@
+// tests/unit/overtime_test.go - Enhanced mode overtime and tiebreaker tests
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+func TestBattleEngine_TiebreakWinner(t *testing.T) {
+	gameObj := models.NewGame("tiebreak", models.EnhancedMode)
+	player1 := models.NewPlayer("p1", "player1", "pass1")
+	player2 := models.NewPlayer("p2", "player2", "pass2")
+	player1.InitializeTowers(nil)
+	player2.InitializeTowers(nil)
+	gameObj.AddPlayer(player1)
+	gameObj.AddPlayer(player2)
+
+	engine := game.NewBattleEngine(1.2)
+	if winner := engine.TiebreakWinner(gameObj); winner != "" {
+		t.Errorf("Expected untouched towers to be a draw, got %s", winner)
+	}
+
+	// p1's weakest tower is at 60%, p2's at 50%
+	player1.Towers[0].HP = player1.Towers[0].MaxHP * 6 / 10
+	player2.Towers[1].HP = player2.Towers[1].MaxHP / 2
+	if winner := engine.TiebreakWinner(gameObj); winner != "p1" {
+		t.Errorf("Expected p1 to win the tiebreaker, got %s", winner)
+	}
+
+	// Destroyed towers are not counted
+	player2.Towers[1].HP = 0
+	if winner := engine.TiebreakWinner(gameObj); winner != "p2" {
+		t.Errorf("Expected p2 to win once its damaged tower is ignored, got %s", winner)
+	}
+}
+
+func TestEnhancedGameManager_TiedGameGoesThroughOvertime(t *testing.T) {
+	manager := game.NewEnhancedGameManager(1.0, 1, 1.2, 50)
+	manager.SetOvertime(1, 2.0, 1)
+	events := game.NewEventManager()
+	manager.SetEventManager(events)
+
+	phases := make(chan game.GameEventData, 2)
+	events.Handle(game.EventPhaseChanged, func(event game.GameEventData) {
+		phases <- event
+	})
+	ended := make(chan game.GameEventData, 1)
+	events.Handle(game.EventGameEnded, func(event game.GameEventData) {
+		ended <- event
+	})
+
+	gameObj := models.NewGameWithSeed("overtime", models.EnhancedMode, 5)
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{models.NewTroop("goblin", "Goblin", 100, 30, 5, 0, 2, "")}
+		gameObj.AddPlayer(player)
+	}
+	if err := manager.StartGame(gameObj); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer manager.CleanupGame(gameObj.ID)
+
+	state, _ := manager.GetGameState(gameObj.ID)
+	if state["phase"] != game.PhaseRegular {
+		t.Errorf("Expected the regular phase, got %v", state["phase"])
+	}
+
+	expected := []struct {
+		phase game.MatchPhase
+		regen float64
+	}{
+		{game.PhaseOvertime, 2.0},
+		{game.PhaseSuddenDeath, 2.0},
+	}
+	for _, want := range expected {
+		select {
+		case event := <-phases:
+			data := event.Data.(map[string]interface{})
+			if data["phase"] != want.phase || data["mana_regen_per_second"] != want.regen {
+				t.Errorf("Expected %s at %.1f mana/s, got %v", want.phase, want.regen, data)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected the game to enter %s", want.phase)
+		}
+	}
+
+	select {
+	case event := <-ended:
+		if reason := event.Data.(map[string]interface{})["reason"]; reason != "tiebreaker" {
+			t.Errorf("Expected the game to end on the tiebreaker, got %v", reason)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the game to end")
+	}
+
+	if gameObj.Winner != nil {
+		t.Errorf("Expected untouched towers to be a draw, got %s", gameObj.Winner.ID)
+	}
+	if !gameObj.Replay.Tiebreaker || gameObj.Replay.SuddenDeathTick == 0 {
+		t.Errorf("Expected the replay to record sudden death and the tiebreaker, got %+v", gameObj.Replay)
+	}
+	if err := game.NewReplayEngine(1.2).Verify(gameObj.Replay); err != nil {
+		t.Errorf("Expected the tiebroken game to verify, got %v", err)
+	}
+}
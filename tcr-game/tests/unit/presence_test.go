@@ -0,0 +1,184 @@
+// tests/unit/presence_test.go - Disconnect grace period and forfeit tests
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+// startPresenceGame starts a game of a mode with a one second grace period
+// for players who disconnect, with both players connected
+func startPresenceGame(t *testing.T, mode models.GameMode) (*game.GameEngine, *models.Game) {
+	engine := newTestEngine(t)
+	balance := *engine.Balance()
+	balance.Version = 2
+	balance.Game.DisconnectGrace = 1
+	engine.SetBalance(&balance)
+
+	gameObj, err := engine.CreateGame("g1", mode)
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	t.Cleanup(func() { engine.CleanupGame("g1") })
+	for _, id := range []string{"p1", "p2"} {
+		if err := engine.JoinGame("g1", models.NewPlayer(id, id, "pass")); err != nil {
+			t.Fatalf("Failed to join %s: %v", id, err)
+		}
+		engine.PlayerConnected("g1", id)
+	}
+	return engine, gameObj
+}
+
+func presenceOf(t *testing.T, engine *game.GameEngine, playerID string) map[string]interface{} {
+	state, err := engine.GetGameState("g1")
+	if err != nil {
+		t.Fatalf("Failed to get state: %v", err)
+	}
+	return state["presence"].(map[string]interface{})[playerID].(map[string]interface{})
+}
+
+func TestSimpleGameManager_PauseHoldsTheTurn(t *testing.T) {
+	manager, gameObj, reasons, _ := startTimedGame(t, 0)
+	defer manager.CleanupGame(gameObj.ID)
+
+	manager.Pause(gameObj)
+	time.Sleep(1200 * time.Millisecond)
+	state := manager.GetGameState(gameObj)
+	if state["current_turn"] != 0 || state["turn_paused"] != true {
+		t.Fatalf("Expected p1's turn to be held while paused, got turn %v", state["current_turn"])
+	}
+
+	manager.Resume(gameObj)
+	if recorded := reasons(); recorded[len(recorded)-1] != "resume" {
+		t.Errorf("Expected the turn to be announced again on resuming, got %v", recorded)
+	}
+	time.Sleep(1200 * time.Millisecond)
+	if state := manager.GetGameState(gameObj); state["current_turn"] != 1 {
+		t.Errorf("Expected the turn to pass once the clock runs again, got %v", state["current_turn"])
+	}
+}
+
+func TestGameEngine_ForfeitsPlayersWhoDoNotReturn(t *testing.T) {
+	engine, gameObj := startPresenceGame(t, models.SimpleMode)
+	events := recordEvents(engine, game.EventPlayerDisconnected, game.EventPlayerReconnected)
+	ended := make(chan game.GameEventData, 1)
+	engine.Events().Handle(game.EventGameEnded, func(event game.GameEventData) {
+		ended <- event
+	})
+
+	engine.PlayerDisconnected("g1", "p2")
+	recorded := events()
+	if len(recorded) != 1 {
+		t.Fatalf("Expected p2's disconnect to be published, got %v", eventTypesOf(recorded))
+	}
+	if data := recorded[0].Data.(map[string]interface{}); data["player_id"] != "p2" || data["grace_seconds"] != 1 {
+		t.Errorf("Expected p2 to have 1 second to return, got %v", data)
+	}
+	if presence := presenceOf(t, engine, "p2"); presence["connected"] != false || presence["forfeit_in"] != 1 {
+		t.Errorf("Expected p2 to be shown away, got %v", presence)
+	}
+	if state, _ := engine.GetGameState("g1"); state["turn_paused"] != true {
+		t.Errorf("Expected the turn clock to wait for p2")
+	}
+
+	select {
+	case event := <-ended:
+		if data := event.Data.(map[string]interface{}); data["reason"] != "abandoned" {
+			t.Errorf("Expected the game to end as abandoned, got %v", data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected p2 to forfeit")
+	}
+	if gameObj.Winner == nil || gameObj.Winner.ID != "p1" {
+		t.Errorf("Expected p1 to win when p2 abandons")
+	}
+
+	// Nobody is kept track of once the game is over
+	if state, _ := engine.GetGameState("g1"); state["presence"] != nil {
+		t.Errorf("Expected no presence in the finished game's state")
+	}
+	engine.PlayerConnected("g1", "p2")
+	if recorded := events(); len(recorded) != 0 {
+		t.Errorf("Expected p2 returning after the game to go unannounced, got %v", eventTypesOf(recorded))
+	}
+}
+
+func TestGameEngine_ReconnectingInTimeResumes(t *testing.T) {
+	engine, gameObj := startPresenceGame(t, models.SimpleMode)
+	events := recordEvents(engine, game.EventPlayerDisconnected, game.EventPlayerReconnected, game.EventTurnChanged)
+
+	// A second connection closing leaves the player connected
+	engine.PlayerConnected("g1", "p1")
+	engine.PlayerDisconnected("g1", "p1")
+	if recorded := events(); len(recorded) != 0 {
+		t.Fatalf("Expected no events while p1 is still connected, got %v", eventTypesOf(recorded))
+	}
+
+	engine.PlayerDisconnected("g1", "p1")
+	engine.PlayerConnected("g1", "p1")
+	recorded := events()
+	expected := []game.EventType{game.EventPlayerDisconnected, game.EventPlayerReconnected, game.EventTurnChanged}
+	if len(recorded) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, eventTypesOf(recorded))
+	}
+	if reason := recorded[2].Data.(map[string]interface{})["reason"]; reason != "resume" {
+		t.Errorf("Expected the turn clock to resume, got %v", reason)
+	}
+	if presence := presenceOf(t, engine, "p1"); presence["connected"] != true || presence["away"] != nil {
+		t.Errorf("Expected p1 to be shown connected, got %v", presence)
+	}
+
+	time.Sleep(1200 * time.Millisecond)
+	if gameObj.State != models.InProgress {
+		t.Errorf("Expected the game to play on after p1 returned, got %s", gameObj.State)
+	}
+}
+
+func TestGameEngine_ForfeitedEnhancedReplayVerifies(t *testing.T) {
+	engine, gameObj := startPresenceGame(t, models.EnhancedMode)
+	ended := make(chan struct{})
+	engine.Events().Handle(game.EventGameEnded, func(event game.GameEventData) {
+		close(ended)
+	})
+
+	engine.PlayerDisconnected("g1", "p1")
+	select {
+	case <-ended:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected p1 to forfeit")
+	}
+
+	if gameObj.Winner == nil || gameObj.Winner.ID != "p2" {
+		t.Fatalf("Expected p2 to win when p1 abandons")
+	}
+	if err := engine.VerifyReplay(gameObj.Replay); err != nil {
+		t.Errorf("Expected the forfeited replay to verify: %v", err)
+	}
+}
+
+func TestGameEngine_KeepsPresenceOnlyForPlayers(t *testing.T) {
+	engine := newTestEngine(t)
+
+	// Connections to a game before it exists or before taking a seat are
+	// not counted once the player joins
+	engine.PlayerConnected("g1", "p1")
+	if _, err := engine.CreateGame("g1", models.SimpleMode); err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	t.Cleanup(func() { engine.CleanupGame("g1") })
+	engine.PlayerConnected("g1", "p2")
+	for _, id := range []string{"p1", "p2"} {
+		if err := engine.JoinGame("g1", models.NewPlayer(id, id, "pass")); err != nil {
+			t.Fatalf("Failed to join %s: %v", id, err)
+		}
+		if presence := presenceOf(t, engine, id); presence["connected"] != false {
+			t.Errorf("Expected %s to be shown disconnected, got %v", id, presence)
+		}
+		if id == "p1" && engine.JoinGame("g1", models.NewPlayer(id, id, "pass")) == nil {
+			t.Errorf("Expected p1 not to take a second seat")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// tests/unit/progression_test.go - Post-match progression tests
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+// awaitRewards waits for the post-match pipeline to record a finished game
+func awaitRewards(t *testing.T, engine *game.GameEngine, gameID string) map[string]*game.MatchRewards {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if rewards, exists := engine.GetMatchRewards(gameID); exists {
+			return rewards
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected rewards for game %s", gameID)
+	return nil
+}
+
+func TestGameEngine_PersistsMatchRewards(t *testing.T) {
+	engine, jsonStorage := playRatedGame(t, config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30, ExpWin: 120, ExpDraw: 5})
+
+	rewards := awaitRewards(t, engine, "rated")
+
+	winnerRewards := rewards["p1"]
+	if winnerRewards.Result != models.ResultWin || winnerRewards.Experience != 120 || !winnerRewards.LeveledUp || winnerRewards.Level != 2 {
+		t.Errorf("Expected a win worth 120 EXP and a level up, got %+v", winnerRewards)
+	}
+	if rewards["p2"].Experience != 0 || rewards["p2"].RatingAfter >= rewards["p2"].RatingBefore {
+		t.Errorf("Expected the loser to gain no EXP and lose rating, got %+v", rewards["p2"])
+	}
+
+	winner, _ := jsonStorage.LoadPlayer("p1")
+	if winner.Experience != 120 || winner.Level != 2 || winner.Stats.GamesPlayed != 1 {
+		t.Errorf("Expected stored EXP 120 at level 2 after 1 game, got %d at level %d after %d", winner.Experience, winner.Level, winner.Stats.GamesPlayed)
+	}
+
+	state, _ := engine.GetGameState("rated")
+	if state["rewards"] == nil {
+		t.Errorf("Expected rewards in the finished game's state")
+	}
+
+	// Ending the game again must not award anything twice
+	engine.EndGame("rated", "admin")
+	winner, _ = jsonStorage.LoadPlayer("p1")
+	if winner.Experience != 120 || winner.Stats.GamesPlayed != 1 {
+		t.Errorf("Expected rewards to be applied once, got EXP %d after %d games", winner.Experience, winner.Stats.GamesPlayed)
+	}
+	engine.CleanupGame("rated")
+	if _, exists := engine.GetMatchRewards("rated"); exists {
+		t.Errorf("Expected the rewards to be forgotten with the game")
+	}
+}
+
+func TestGameEngine_RetiresFinishedGames(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.SetGameRetention(50 * time.Millisecond)
+	engine.CreateGame("g1", models.SimpleMode)
+	for _, id := range []string{"p1", "p2"} {
+		engine.JoinGame("g1", models.NewPlayer(id, id, "pass"))
+	}
+
+	if err := engine.EndGame("g1", "admin"); err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+	if state, err := engine.GetGameState("g1"); err != nil || state["state"] != models.Finished {
+		t.Fatalf("Expected the finished game to stay loaded for a while, got %v (%v)", state, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := engine.GetGame("g1"); err != nil {
+			if seq := engine.Events().LastSeq("g1"); seq != 0 {
+				t.Errorf("Expected the game's events to be cleaned up, got seq %d", seq)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected the finished game to be retired")
+}
@@ -0,0 +1,107 @@
+// tests/unit/rating_test.go - Elo rating tests
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func TestRatingSystem_WinAndDraw(t *testing.T) {
+	ratings := game.NewRatingSystem(config.RatingConfig{KFactor: 32})
+	winner := models.NewPlayer("p1", "player1", "pass1")
+	loser := models.NewPlayer("p2", "player2", "pass2")
+
+	changes, err := ratings.RateGame("g1", models.SimpleMode, "p1", winner, loser, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to rate game: %v", err)
+	}
+	if winner.RatingFor(models.SimpleMode).Value != 1216 || loser.RatingFor(models.SimpleMode).Value != 1184 {
+		t.Errorf("Expected 1216 and 1184, got %d and %d", winner.RatingFor(models.SimpleMode).Value, loser.RatingFor(models.SimpleMode).Value)
+	}
+	if changes["p2"].Result != models.ResultLoss || changes["p2"].OpponentRating != models.DefaultRating {
+		t.Errorf("Expected a recorded loss against a 1200 player, got %+v", changes["p2"])
+	}
+	if winner.RatingFor(models.EnhancedMode).Value != models.DefaultRating {
+		t.Errorf("Expected enhanced rating to be untouched")
+	}
+
+	// The favourite loses rating when held to a draw
+	before := winner.RatingFor(models.SimpleMode).Value
+	ratings.RateGame("g2", models.SimpleMode, "", winner, loser, time.Now())
+	if winner.RatingFor(models.SimpleMode).Value >= before {
+		t.Errorf("Expected the higher rated player to lose rating on a draw")
+	}
+	if len(winner.RatingFor(models.SimpleMode).History) != 2 {
+		t.Errorf("Expected 2 history entries, got %d", len(winner.RatingFor(models.SimpleMode).History))
+	}
+
+	if _, err := ratings.RateGame("g3", models.SimpleMode, "p9", winner, loser, time.Now()); err == nil {
+		t.Errorf("Expected an unknown winner to be rejected")
+	}
+}
+
+// playRatedGame stores two players and plays a simple game between them
+// through the engine; p1 attacks first and wins
+func playRatedGame(t *testing.T, simple config.SimpleGameConfig) (*game.GameEngine, *storage.JSONStorage) {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Simple:   simple,
+			Enhanced: config.EnhancedGameConfig{CritMultiplier: 1.2},
+		},
+		Troops: models.NewTroopCatalogue([]*models.Troop{
+			models.NewTroop("giant", "Giant", 2000, 400, 5, 0, 5, ""),
+		}),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	engine.CreateGame("rated", models.SimpleMode)
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		jsonStorage.SavePlayer(player)
+		if err := engine.JoinGame("rated", player); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+	}
+
+	gameObj, _ := engine.GetGame("rated")
+	battleEngine := game.NewBattleEngine(1.2)
+	for turn := 0; turn < 20 && !gameObj.IsFinished(); turn++ {
+		current := gameObj.Players[gameObj.CurrentTurn]
+		targets := battleEngine.GetValidTargets(gameObj, gameObj.GetOpponent(current.ID).ID)
+		action := game.TurnAction{Type: "attack", TroopID: "giant", TargetTower: targets[0]}
+		if _, err := engine.ProcessSimpleAction("rated", current.ID, action); err != nil {
+			t.Fatalf("Turn %d failed: %v", turn, err)
+		}
+	}
+	if !gameObj.IsFinished() || gameObj.Winner == nil || gameObj.Winner.ID != "p1" {
+		t.Fatalf("Expected p1 to win by attacking first")
+	}
+
+	awaitRewards(t, engine, "rated")
+	return engine, jsonStorage
+}
+
+func TestGameEngine_RecordsRatingsAtGameEnd(t *testing.T) {
+	_, jsonStorage := playRatedGame(t, config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30})
+
+	winner, _ := jsonStorage.LoadPlayer("p1")
+	loser, _ := jsonStorage.LoadPlayer("p2")
+	if winner.Stats.GamesWon != 1 || loser.Stats.GamesLost != 1 {
+		t.Errorf("Expected stored stats to record the result, got %+v and %+v", winner.Stats, loser.Stats)
+	}
+	if winner.Ratings[models.SimpleMode].Value <= models.DefaultRating || loser.Ratings[models.SimpleMode].Value >= models.DefaultRating {
+		t.Errorf("Expected stored ratings to move apart")
+	}
+	if len(winner.Towers) != 0 {
+		t.Errorf("Expected in-game towers not to be persisted")
+	}
+}
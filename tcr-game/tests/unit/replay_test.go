@@ -0,0 +1,107 @@
+// tests/unit/replay_test.go - Replay recording and re-simulation tests
+package unit
+
+import (
+	"testing"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func playSimpleGame(t *testing.T, seed int64) *models.Game {
+	manager := game.NewSimpleGameManager(2, 30, 0, 1.2)
+	engine := game.NewBattleEngine(1.2)
+	gameObj := models.NewGameWithSeed("replay_game", models.SimpleMode, seed)
+
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		for _, troopID := range []string{"goblin", "archer", "knight", "wizard"} {
+			player.AvailableTroops = append(player.AvailableTroops, &models.Troop{ID: troopID, HP: 100, MaxHP: 100, Attack: 120})
+		}
+		gameObj.AddPlayer(player)
+	}
+
+	if err := manager.StartGame(gameObj); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	for turn := 0; turn < 100 && !gameObj.IsFinished(); turn++ {
+		current := gameObj.Players[gameObj.CurrentTurn]
+		targets := engine.GetValidTargets(gameObj, gameObj.GetOpponent(current.ID).ID)
+		var troopID string
+		for _, troop := range current.AvailableTroops {
+			if troop.IsAlive() {
+				troopID = troop.ID
+				break
+			}
+		}
+		action := game.TurnAction{
+			Type:        "attack",
+			TroopID:     troopID,
+			TargetTower: targets[0],
+		}
+		result, err := manager.ProcessTurn(gameObj, current.ID, action)
+		if err != nil || !result.Success {
+			t.Fatalf("Turn %d failed: %v %v", turn, err, result)
+		}
+	}
+
+	if !gameObj.IsFinished() {
+		t.Fatalf("Expected game to finish")
+	}
+	return gameObj
+}
+
+func TestReplayEngine_VerifiesRecordedGame(t *testing.T) {
+	gameObj := playSimpleGame(t, 99)
+	replay := gameObj.Replay
+
+	if replay == nil || replay.Result == nil {
+		t.Fatalf("Expected a finished replay log")
+	}
+	if replay.Seed != 99 {
+		t.Errorf("Expected seed 99, got %d", replay.Seed)
+	}
+	if len(replay.Actions) == 0 {
+		t.Fatalf("Expected recorded actions")
+	}
+	if replay.Result.WinnerID != gameObj.Winner.ID {
+		t.Errorf("Expected recorded winner %s, got %s", gameObj.Winner.ID, replay.Result.WinnerID)
+	}
+
+	replayEngine := game.NewReplayEngine(1.2)
+	if err := replayEngine.Verify(replay); err != nil {
+		t.Errorf("Expected replay to verify, got %v", err)
+	}
+
+	replay.Result.TowerHP["p1"][0]++
+	if err := replayEngine.Verify(replay); err == nil {
+		t.Errorf("Expected tampered replay to fail verification")
+	}
+}
+
+func TestGameStorage_ReplayRoundTrip(t *testing.T) {
+	gameObj := playSimpleGame(t, 5)
+	gameStorage := storage.NewGameStorage(t.TempDir())
+
+	if err := gameStorage.SaveReplay(gameObj.Replay); err != nil {
+		t.Fatalf("Failed to save replay: %v", err)
+	}
+
+	loaded, err := gameStorage.LoadReplay(gameObj.ID)
+	if err != nil {
+		t.Fatalf("Failed to load replay: %v", err)
+	}
+
+	if loaded.Version != models.ReplayVersion {
+		t.Errorf("Expected version %d, got %d", models.ReplayVersion, loaded.Version)
+	}
+	if len(loaded.Actions) != len(gameObj.Replay.Actions) {
+		t.Errorf("Expected %d actions, got %d", len(gameObj.Replay.Actions), len(loaded.Actions))
+	}
+
+	if err := game.NewReplayEngine(1.2).Verify(loaded); err != nil {
+		t.Errorf("Expected loaded replay to verify, got %v", err)
+	}
+}
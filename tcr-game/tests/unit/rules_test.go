@@ -0,0 +1,168 @@
+// tests/unit/rules_test.go - Pluggable rule set tests
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+const raceMode models.GameMode = "race"
+
+// raceRules is a minimal mode: the first player to score twice wins
+type raceRules struct {
+	events *game.EventManager
+	scores map[string]int
+	mutex  sync.Mutex
+}
+
+type raceAction struct {
+	Type string `json:"type"`
+}
+
+type raceResult struct {
+	Success bool `json:"success"`
+	Score   int  `json:"score"`
+}
+
+func (r *raceResult) Accepted() bool             { return r.Success }
+func (r *raceResult) Battle() *game.BattleResult { return nil }
+
+func init() {
+	game.RegisterRuleSet(game.RuleSetDefinition{
+		Mode: raceMode,
+		Experience: func(balance *game.Balance) game.ExperienceRewards {
+			return game.ExperienceRewards{Win: 40}
+		},
+		New: func(balance *game.Balance, events *game.EventManager) game.RuleSet {
+			return &raceRules{events: events, scores: make(map[string]int)}
+		},
+	})
+}
+
+func (rr *raceRules) StartGame(gameObj *models.Game) error {
+	gameObj.Start()
+	return nil
+}
+
+func (rr *raceRules) ValidateAction(gameObj *models.Game, playerID string, payload json.RawMessage) (interface{}, error) {
+	var action raceAction
+	if err := json.Unmarshal(payload, &action); err != nil || action.Type != "score" {
+		return nil, errors.New("invalid action type")
+	}
+	return action, nil
+}
+
+func (rr *raceRules) ApplyAction(gameObj *models.Game, playerID string, action interface{}) (game.ActionResult, error) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	if gameObj.State != models.InProgress {
+		return &raceResult{}, nil
+	}
+	rr.scores[playerID]++
+	if ended, winner := rr.checkEnd(); ended {
+		for _, player := range gameObj.Players {
+			if player.ID == winner {
+				gameObj.Winner = player
+			}
+		}
+		rr.finish(gameObj, "scored")
+	}
+	return &raceResult{Success: true, Score: rr.scores[playerID]}, nil
+}
+
+func (rr *raceRules) checkEnd() (bool, string) {
+	for playerID, score := range rr.scores {
+		if score >= 2 {
+			return true, playerID
+		}
+	}
+	return false, ""
+}
+
+func (rr *raceRules) State(gameObj *models.Game) (map[string]interface{}, error) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+	return map[string]interface{}{"mode": gameObj.Mode, "state": gameObj.State, "scores": len(rr.scores)}, nil
+}
+
+func (rr *raceRules) End(gameObj *models.Game, reason string) error {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+	rr.finish(gameObj, reason)
+	return nil
+}
+
+func (rr *raceRules) finish(gameObj *models.Game, reason string) {
+	gameObj.State = models.Finished
+	endTime := time.Now()
+	gameObj.EndTime = &endTime
+	rr.events.PublishGameEnded(gameObj, reason)
+}
+
+func (rr *raceRules) CleanupGame(gameID string) {}
+
+func TestGameEngine_PlaysRegisteredRuleSet(t *testing.T) {
+	if !game.IsRegisteredMode(models.SimpleMode) || !game.IsRegisteredMode(models.EnhancedMode) || !game.IsRegisteredMode(raceMode) {
+		t.Fatalf("Expected the built-in modes and race to be registered")
+	}
+	if game.IsRegisteredMode("chess") {
+		t.Errorf("Expected an unregistered mode to be rejected")
+	}
+
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game:    config.GameConfig{Enhanced: config.EnhancedGameConfig{CritMultiplier: 1.2}},
+		Troops:  models.NewTroopCatalogue([]*models.Troop{models.NewTroop("goblin", "Goblin", 100, 30, 5, 0, 2, "")}),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	if _, err := engine.CreateGame("chess", "chess"); err == nil {
+		t.Errorf("Expected a game of an unregistered mode not to be created")
+	}
+
+	engine.CreateGame("race1", raceMode)
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		jsonStorage.SavePlayer(player)
+		if err := engine.JoinGame("race1", player); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+	}
+
+	if _, err := engine.ProcessAction("race1", "p1", json.RawMessage(`{"type":"attack"}`)); err == nil {
+		t.Errorf("Expected the rule set to reject another mode's action")
+	}
+	if _, err := engine.ProcessSimpleAction("race1", "p1", game.TurnAction{Type: "attack"}); err == nil {
+		t.Errorf("Expected a simple turn to be rejected in a race game")
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := engine.ProcessAction("race1", "p1", json.RawMessage(`{"type":"score"}`))
+		if err != nil || !result.Accepted() {
+			t.Fatalf("Expected the score to be accepted, got %+v (%v)", result, err)
+		}
+	}
+
+	state, err := engine.GetGameState("race1")
+	if err != nil || state["state"] != models.Finished {
+		t.Fatalf("Expected the race to be finished, got %v (%v)", state, err)
+	}
+
+	// The post-match pipeline applies the mode's experience
+	rewards := awaitRewards(t, engine, "race1")
+	if rewards["p1"].Result != models.ResultWin || rewards["p1"].Experience != 40 {
+		t.Errorf("Expected p1 to win 40 experience, got %+v", rewards["p1"])
+	}
+}
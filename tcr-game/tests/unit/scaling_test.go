@@ -0,0 +1,85 @@
+// tests/unit/scaling_test.go - Stat scaling and EXP curve tests
+package unit
+
+import (
+	"testing"
+
+	"tcr-game/internal/models"
+	"tcr-game/internal/utils"
+)
+
+func TestApplyLevel_DoesNotCompound(t *testing.T) {
+	troop := models.NewTroop("knight", "Knight", 150, 35, 10, 0.05, 4, "")
+	troop.ApplyLevel(3)
+	troop.ApplyLevel(3)
+	if troop.MaxHP != 180 || troop.Attack != 42 || troop.Defense != 12 {
+		t.Errorf("Expected 180/42/12 after applying level 3 twice, got %d/%d/%d", troop.MaxHP, troop.Attack, troop.Defense)
+	}
+
+	troop.ApplyLevel(1)
+	if troop.MaxHP != 150 || troop.Attack != 35 {
+		t.Errorf("Expected base stats back at level 1, got %d/%d", troop.MaxHP, troop.Attack)
+	}
+
+	tower := models.NewTower(models.KingTower, "King", 500, 25, 15, 0.1, "", 2)
+	tower.ApplyLevel(2)
+	tower.ApplyLevel(2)
+	if tower.MaxHP != 550 || tower.Attack != 27 {
+		t.Errorf("Expected 550/27 after applying level 2 twice, got %d/%d", tower.MaxHP, tower.Attack)
+	}
+}
+
+func TestScaleStats_UsesGrowthCurvesFromData(t *testing.T) {
+	catalogue, err := loadTroopsFrom(t, `[
+		{"id": "golem", "name": "Golem", "hp": 200, "attack": 20, "defense": 10, "crit_chance": 0, "mana_cost": 5, "growth": {"hp": 0.25, "attack": 0}}
+	]`)
+	if err != nil {
+		t.Fatalf("Failed to load troops: %v", err)
+	}
+
+	golem, _ := catalogue.Get("golem")
+	golem.ApplyLevel(3)
+	// Defense was left out of the curve and keeps the default growth
+	if golem.MaxHP != 300 || golem.Attack != 20 || golem.Defense != 12 {
+		t.Errorf("Expected 300/20/12 at level 3, got %d/%d/%d", golem.MaxHP, golem.Attack, golem.Defense)
+	}
+
+	if _, err := loadTroopsFrom(t, `[
+		{"id": "golem", "name": "Golem", "hp": 200, "attack": 20, "defense": 10, "crit_chance": 0, "mana_cost": 5, "growth": {"hp": -1}}
+	]`); err == nil {
+		t.Errorf("Expected negative growth to be rejected")
+	}
+
+	towers := models.DefaultTowerCatalogue()
+	king := towers.Specs[models.KingTower]
+	king.Growth = &models.StatGrowth{HP: 0.5}
+	towers.Specs[models.KingTower] = king
+
+	player := models.NewPlayer("p1", "player1", "pass1")
+	player.TowerLevels[models.KingTower] = 2
+	player.InitializeTowers(towers)
+	if tower := player.KingTower(); tower.MaxHP != 750 || tower.Attack != 25 {
+		t.Errorf("Expected the king tower's own curve, got %d/%d", tower.MaxHP, tower.Attack)
+	}
+}
+
+func TestExpCurve_MatchesLeveling(t *testing.T) {
+	if utils.CalculateRequiredExp(3) != models.ExpToNextLevel(3) || models.ExpToNextLevel(3) != 121 {
+		t.Errorf("Expected 121 EXP from level 3 to 4, got %d", utils.CalculateRequiredExp(3))
+	}
+
+	player := models.NewPlayer("p1", "player1", "pass1")
+	player.AddExperience(209)
+	if player.Level != 2 {
+		t.Errorf("Expected level 2 just short of 210 EXP, got %d", player.Level)
+	}
+	player.AddExperience(1)
+	if player.Level != 3 {
+		t.Errorf("Expected level 3 at 210 EXP, got %d", player.Level)
+	}
+
+	level, into, needed := models.LevelProgress(250)
+	if level != 3 || into != 40 || needed != 121 {
+		t.Errorf("Expected level 3 with 40 of 121 EXP, got level %d with %d of %d", level, into, needed)
+	}
+}
@@ -0,0 +1,237 @@
+// tests/unit/team_test.go - 2v2 team match tests
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+// startTeamGame creates a team game and seats a1 and a2 against b1 and b2
+func startTeamGame(t *testing.T) *game.GameEngine {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	balance := &game.Balance{
+		Version: 1,
+		Game: config.GameConfig{
+			Simple:   config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30},
+			Enhanced: config.EnhancedGameConfig{CritMultiplier: 1.2},
+			Team:     config.TeamGameConfig{ExpWin: 25, ExpDraw: 5},
+		},
+		Troops: deckCatalogue(),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	if _, err := engine.CreateGame("team1", models.TeamMode); err != nil {
+		t.Fatalf("Failed to create team game: %v", err)
+	}
+	for _, id := range []string{"a1", "b1", "a2", "b2"} {
+		player := models.NewPlayer(id, id, "pass")
+		jsonStorage.SavePlayer(player)
+		if err := engine.JoinGame("team1", player); err != nil {
+			t.Fatalf("Failed to join %s: %v", id, err)
+		}
+	}
+	return engine
+}
+
+// newTeamGame builds a started team game without an engine
+func newTeamGame() *models.Game {
+	gameObj := models.NewGame("team_battle", models.TeamMode)
+	gameObj.MaxPlayers = 4
+	gameObj.TeamSize = 2
+	for _, id := range []string{"a1", "b1", "a2", "b2"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{models.NewTroop("knight", "Knight", 100, 30, 5, 0, 1, "")}
+		gameObj.AddPlayer(player)
+	}
+	gameObj.Start()
+	return gameObj
+}
+
+func TestTeamMode_SeatsAndSharedKing(t *testing.T) {
+	engine := startTeamGame(t)
+	defer engine.CleanupGame("team1")
+
+	gameObj, _ := engine.GetGame("team1")
+	if gameObj.State != models.InProgress {
+		t.Fatalf("Expected the game to start once four players joined, got %s", gameObj.State)
+	}
+	if err := engine.JoinGame("team1", models.NewPlayer("c1", "c1", "pass")); err == nil {
+		t.Errorf("Expected a fifth player to be turned away")
+	}
+
+	for i, want := range []int{0, 1, 0, 1} {
+		if team := gameObj.TeamOf(gameObj.Players[i].ID); team != want {
+			t.Errorf("Expected %s on team %d, got %d", gameObj.Players[i].ID, want, team)
+		}
+	}
+
+	a1, a2 := gameObj.Players[0], gameObj.Players[2]
+	if len(a1.Towers) != 2 || len(a2.Towers) != 2 {
+		t.Fatalf("Expected each teammate to own a guard and the king, got %d and %d towers", len(a1.Towers), len(a2.Towers))
+	}
+	if a1.Towers[0].Position != 0 || a2.Towers[0].Position != 1 {
+		t.Errorf("Expected teammates to guard different lanes")
+	}
+	if a1.KingTower() != a2.KingTower() {
+		t.Errorf("Expected teammates to share a king tower")
+	}
+	if towers := gameObj.TeamTowers(0); len(towers) != 3 {
+		t.Errorf("Expected a side to have 3 towers, got %d", len(towers))
+	}
+}
+
+func TestTeamMode_ActionsNameTheirTarget(t *testing.T) {
+	engine := startTeamGame(t)
+	defer engine.CleanupGame("team1")
+
+	gameObj, _ := engine.GetGame("team1")
+	troopID := gameObj.Players[0].AvailableTroops[0].ID
+
+	action := func(target string) game.ActionResult {
+		payload, _ := json.Marshal(game.TurnAction{Type: "attack", TroopID: troopID, TargetPlayer: target, TargetTower: 0})
+		result, err := engine.ProcessAction("team1", "a1", payload)
+		if err != nil {
+			t.Fatalf("Failed to process action: %v", err)
+		}
+		return result
+	}
+
+	if result := action(""); result.Accepted() {
+		t.Errorf("Expected an attack without a target player to be rejected")
+	}
+	if result := action("a2"); result.Accepted() {
+		t.Errorf("Expected an attack on a teammate to be rejected")
+	}
+
+	result := action("b2")
+	if !result.Accepted() {
+		t.Fatalf("Expected an attack on an opponent to be accepted, got %+v", result)
+	}
+	battle := result.Battle()
+	if battle.DefenderID != "b2" || battle.AttackerTeam != 0 || battle.DefenderTeam != 1 {
+		t.Errorf("Expected a1 of team 0 to hit b2 of team 1, got %+v", battle)
+	}
+
+	// Turns alternate between the sides
+	if next := result.(*game.TurnResult).NextPlayer; next != "b1" {
+		t.Errorf("Expected b1 to play next, got %s", next)
+	}
+
+	state, _ := engine.GetGameState("team1")
+	teams := state["teams"].([]map[string]interface{})
+	if len(teams) != 2 || teams[1]["players"].([]string)[1] != "b2" {
+		t.Errorf("Expected the state to list both sides, got %v", teams)
+	}
+	targets := state["current_player"].(map[string]interface{})["valid_targets"].(map[string][]int)
+	if len(targets) != 2 || len(targets["a1"]) != 1 {
+		t.Errorf("Expected b1 to have targets on both opponents, got %v", targets)
+	}
+}
+
+func TestTeamMode_SharedKingDecidesTheGame(t *testing.T) {
+	engine := game.NewBattleEngine(1.2)
+	gameObj := newTeamGame()
+	b2 := gameObj.Players[3]
+
+	b2.Towers[0].HP = 0
+	b2.KingTower().HP = 1
+
+	result, err := engine.ExecuteAttack(gameObj, "a2", "b2", "knight", 1)
+	if err != nil {
+		t.Fatalf("Failed to attack: %v", err)
+	}
+	if !result.GameEnded || result.Winner != "a1" {
+		t.Errorf("Expected team 0 to win, recorded under a1, got %+v", result)
+	}
+	if king := gameObj.Players[1].KingTower(); king.IsAlive() {
+		t.Errorf("Expected b1 to lose the shared king too")
+	}
+}
+
+func TestTeamMode_WinnerOnTowersLost(t *testing.T) {
+	engine := game.NewBattleEngine(1.2)
+	gameObj := newTeamGame()
+
+	if winner := engine.GetGameWinner(gameObj); winner != "" {
+		t.Errorf("Expected no winner while even, got %s", winner)
+	}
+
+	gameObj.Players[2].Towers[0].HP = 0
+	if winner := engine.GetGameWinner(gameObj); winner != "b1" {
+		t.Errorf("Expected team 1 to lead on towers lost, got %s", winner)
+	}
+	if lost := engine.CountTeamDestroyedTowers(gameObj, 0); lost != 1 {
+		t.Errorf("Expected team 0 to have lost 1 tower, got %d", lost)
+	}
+}
+
+func TestTeamMode_ReplayVerifies(t *testing.T) {
+	engine := startTeamGame(t)
+	defer engine.CleanupGame("team1")
+
+	gameObj, _ := engine.GetGame("team1")
+	targets := map[string]string{"a1": "b1", "b1": "a1", "a2": "b2", "b2": "a2"}
+	for turn := 0; turn < 4; turn++ {
+		player := gameObj.Players[gameObj.CurrentTurn]
+		payload, _ := json.Marshal(game.TurnAction{Type: "attack", TroopID: player.AvailableTroops[0].ID, TargetPlayer: targets[player.ID]})
+		if result, err := engine.ProcessAction("team1", player.ID, payload); err != nil || !result.Accepted() {
+			t.Fatalf("Expected %s's attack to be accepted, got %+v (%v)", player.ID, result, err)
+		}
+	}
+	if err := engine.EndGame("team1", "test"); err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+
+	// Every player is rewarded for the draw
+	rewards := awaitRewards(t, engine, "team1")
+	if len(rewards) != 4 || rewards["b2"].Result != models.ResultDraw || rewards["b2"].Experience != 5 {
+		t.Errorf("Expected all four players to be rewarded a draw, got %v", rewards)
+	}
+
+	replay, err := engine.GetReplay("team1")
+	if err != nil {
+		t.Fatalf("Failed to load replay: %v", err)
+	}
+	if replay.TeamSize != 2 || len(replay.Actions) == 0 {
+		t.Fatalf("Expected a team replay with actions, got team size %d and %d actions", replay.TeamSize, len(replay.Actions))
+	}
+	if err := engine.VerifyReplay(replay); err != nil {
+		t.Errorf("Expected the team replay to verify, got %v", err)
+	}
+}
+
+func TestRatingSystem_RateTeamGame(t *testing.T) {
+	ratings := game.NewRatingSystem(config.RatingConfig{KFactor: 32})
+	a1, a2 := models.NewPlayer("a1", "a1", ""), models.NewPlayer("a2", "a2", "")
+	b1, b2 := models.NewPlayer("b1", "b1", ""), models.NewPlayer("b2", "b2", "")
+
+	changes, err := ratings.RateTeamGame("g1", models.TeamMode, 0, [][]*models.Player{{a1, a2}, {b1, b2}}, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to rate team game: %v", err)
+	}
+	for _, id := range []string{"a1", "a2"} {
+		if change := changes[id]; change.Result != models.ResultWin || change.After != 1216 {
+			t.Errorf("Expected %s to win 16 points, got %+v", id, change)
+		}
+	}
+	for _, id := range []string{"b1", "b2"} {
+		if change := changes[id]; change.Result != models.ResultLoss || change.After != 1184 {
+			t.Errorf("Expected %s to lose 16 points, got %+v", id, change)
+		}
+	}
+	if b2.RatingFor(models.TeamMode).Value != 1184 {
+		t.Errorf("Expected the rating to be applied")
+	}
+
+	if _, err := ratings.RateTeamGame("g2", models.TeamMode, 0, [][]*models.Player{{a1, b1}, {b1, b2}}, time.Now()); err == nil {
+		t.Errorf("Expected a player on both sides to be rejected")
+	}
+}
@@ -0,0 +1,113 @@
+// tests/unit/turn_clock_test.go - Simple mode turn timer tests
+package unit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+)
+
+// startTimedGame starts a simple game with a one second turn clock and
+// records the reason of every turn change. The channel is closed when the
+// game ends.
+func startTimedGame(t *testing.T, maxTimeouts int) (*game.SimpleGameManager, *models.Game, func() []string, chan struct{}) {
+	manager := game.NewSimpleGameManager(2, 1, maxTimeouts, 1.2)
+	events := game.NewEventManager()
+	manager.SetEventManager(events)
+
+	var reasons []string
+	var mutex sync.Mutex
+	events.Handle(game.EventTurnChanged, func(event game.GameEventData) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reasons = append(reasons, event.Data.(map[string]interface{})["reason"].(string))
+	})
+	ended := make(chan struct{})
+	events.Handle(game.EventGameEnded, func(event game.GameEventData) {
+		close(ended)
+	})
+
+	gameObj := models.NewGameWithSeed("timed", models.SimpleMode, 3)
+	for _, id := range []string{"p1", "p2"} {
+		player := models.NewPlayer(id, id, "pass")
+		player.AvailableTroops = []*models.Troop{models.NewTroop("goblin", "Goblin", 100, 30, 5, 0, 2, "")}
+		gameObj.AddPlayer(player)
+	}
+	if err := manager.StartGame(gameObj); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	return manager, gameObj, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), reasons...)
+	}, ended
+}
+
+func TestSimpleGameManager_TimeoutPassesThenForfeits(t *testing.T) {
+	manager, gameObj, reasons, ended := startTimedGame(t, 2)
+	defer manager.CleanupGame(gameObj.ID)
+
+	if remaining, ok := manager.TurnRemaining(gameObj.ID); !ok || remaining != 1 {
+		t.Errorf("Expected 1 second on the clock, got %d", remaining)
+	}
+
+	// p1 idles and the turn passes to p2
+	time.Sleep(1200 * time.Millisecond)
+	state := manager.GetGameState(gameObj)
+	if state["current_turn"] != 1 {
+		t.Fatalf("Expected the turn to pass to p2, got %v", state["current_turn"])
+	}
+
+	targets := game.NewBattleEngine(1.2).GetValidTargets(gameObj, "p1")
+	result, _ := manager.ProcessTurn(gameObj, "p2", game.TurnAction{Type: "attack", TroopID: "goblin", TargetTower: targets[0]})
+	if !result.Success {
+		t.Fatalf("Expected p2's turn to be accepted, got %s", result.Error)
+	}
+
+	// p1 idles a second time in a row and forfeits
+	select {
+	case <-ended:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the game to end")
+	}
+	if gameObj.Winner == nil || gameObj.Winner.ID != "p2" {
+		t.Fatalf("Expected p1 to forfeit to p2")
+	}
+	if _, ok := manager.TurnRemaining(gameObj.ID); ok {
+		t.Errorf("Expected the clock to stop when the game ended")
+	}
+
+	expected := []string{"start", "timeout", "action"}
+	got := reasons()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected turn changes %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected turn changes %v, got %v", expected, got)
+			break
+		}
+	}
+
+	// The forfeit survives replay verification
+	if err := game.NewReplayEngine(1.2).Verify(gameObj.Replay); err != nil {
+		t.Errorf("Expected the forfeited game to verify, got %v", err)
+	}
+}
+
+func TestSimpleGameManager_EndGameStopsClock(t *testing.T) {
+	manager, gameObj, reasons, _ := startTimedGame(t, 1)
+
+	if err := manager.EndGame(gameObj, "admin"); err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+
+	time.Sleep(1200 * time.Millisecond)
+	if len(reasons()) != 1 {
+		t.Errorf("Expected no turn changes after the game ended, got %v", reasons())
+	}
+}
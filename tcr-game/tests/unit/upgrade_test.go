@@ -0,0 +1,103 @@
+// tests/unit/upgrade_test.go - Currency and upgrade tests
+package unit
+
+import (
+	"testing"
+
+	"tcr-game/config"
+	"tcr-game/internal/game"
+	"tcr-game/internal/models"
+	"tcr-game/internal/storage"
+)
+
+func TestUpgradeCatalogue_Validate(t *testing.T) {
+	if err := models.DefaultUpgradeCatalogue().Validate(); err != nil {
+		t.Errorf("Expected default catalogue to be valid, got %v", err)
+	}
+
+	gap := models.DefaultUpgradeCatalogue()
+	gap.TroopLevels[1].Level = 4
+	if err := gap.Validate(); err == nil {
+		t.Errorf("Expected a gap in troop levels to be rejected")
+	}
+
+	negative := models.DefaultUpgradeCatalogue()
+	negative.TowerLevels[0].Gold = -1
+	if err := negative.Validate(); err == nil {
+		t.Errorf("Expected a negative cost to be rejected")
+	}
+}
+
+func TestGameEngine_AwardsMatchCurrency(t *testing.T) {
+	engine, jsonStorage := playRatedGame(t, config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30, ExpWin: 120})
+	rewards, _ := engine.GetMatchRewards("rated")
+	currency := models.DefaultUpgradeCatalogue().Rewards
+
+	// The winner also levels up, which pays a bonus
+	if rewards["p1"].Gold != currency.GoldWin+currency.LevelUpGold || rewards["p1"].Cards["giant"] != currency.CardsWin {
+		t.Errorf("Expected win currency plus a level up bonus, got %+v", rewards["p1"])
+	}
+
+	loser, _ := jsonStorage.LoadPlayer("p2")
+	if loser.Gold != currency.GoldLoss || loser.Cards["giant"] != currency.CardsLoss {
+		t.Errorf("Expected stored loss currency, got %d gold and %v", loser.Gold, loser.Cards)
+	}
+}
+
+func TestGameEngine_UpgradePlayer(t *testing.T) {
+	dir := t.TempDir()
+	jsonStorage := storage.NewJSONStorage(dir, "", "", "")
+	dragon := models.NewTroop("dragon", "Dragon", 300, 60, 10, 0.1, 5, "")
+	dragon.UnlockLevel = 5
+	balance := &game.Balance{
+		Version: 1,
+		Game:    config.GameConfig{Simple: config.SimpleGameConfig{MaxPlayers: 2, TurnTime: 30}},
+		Troops: models.NewTroopCatalogue([]*models.Troop{
+			models.NewTroop("goblin", "Goblin", 100, 30, 5, 0.1, 2, ""),
+			dragon,
+		}),
+	}
+	engine := game.NewGameEngine(&config.Config{}, jsonStorage, storage.NewGameStorage(dir), balance)
+	t.Cleanup(engine.WaitForResults)
+
+	player := models.NewPlayer("p1", "player1", "pass1")
+	player.Gold = 150
+	player.AddCards("goblin", 6)
+	if err := jsonStorage.SavePlayer(player); err != nil {
+		t.Fatalf("Failed to save player: %v", err)
+	}
+
+	result, err := engine.UpgradePlayer("p1", game.UpgradeRequest{Kind: models.UpgradeTroop, TroopID: "goblin"})
+	if err != nil {
+		t.Fatalf("Failed to upgrade: %v", err)
+	}
+	if result.Level != 2 || result.Gold != 50 || result.Cards != 1 {
+		t.Errorf("Expected level 2 with 50 gold and 1 card left, got %+v", result)
+	}
+
+	rejected := []game.UpgradeRequest{
+		{Kind: models.UpgradeTroop, TroopID: "goblin"},           // not enough gold or cards
+		{Kind: models.UpgradeTroop, TroopID: "dragon"},           // still locked
+		{Kind: models.UpgradeTroop, TroopID: "wizard"},           // unknown
+		{Kind: models.UpgradeTower, TowerType: "castle"},         // unknown
+		{Kind: "spell", TroopID: "goblin"},                       // invalid kind
+		{Kind: models.UpgradeTower, TowerType: models.KingTower}, // not enough gold
+	}
+	for _, request := range rejected {
+		if _, err := engine.UpgradePlayer("p1", request); err == nil {
+			t.Errorf("Expected %+v to be rejected", request)
+		}
+	}
+
+	// The new level applies the next time the player joins a game
+	engine.CreateGame("g1", models.SimpleMode)
+	stored, _ := jsonStorage.LoadPlayer("p1")
+	if err := engine.JoinGame("g1", stored); err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+	for _, troop := range stored.AvailableTroops {
+		if troop.ID == "goblin" && troop.Level != 2 {
+			t.Errorf("Expected goblin at level 2 in game, got %d", troop.Level)
+		}
+	}
+}
@@ -8,6 +8,7 @@ class TCRGame {
         this.selectedTroop = null;
         this.selectedTower = null;
         this.gameState = null;
+        this.lastSeq = null; // seq of the last game event received
         
         this.initEventListeners();
         this.showScreen('login-screen');
@@ -149,6 +150,7 @@ class TCRGame {
             
             if (data.success) {
                 this.gameId = gameId;
+                this.lastSeq = null;
                 this.connectWebSocket();
                 this.showScreen('game-screen');
                 this.showStatus(`Game created: ${gameId}`, 'success');
@@ -182,6 +184,7 @@ class TCRGame {
             
             if (data.success) {
                 this.gameId = gameId;
+                this.lastSeq = null;
                 this.connectWebSocket();
                 this.showScreen('game-screen');
             } else {
@@ -195,7 +198,9 @@ class TCRGame {
 
     connectWebSocket() {
         const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-        const wsUrl = `${protocol}//${window.location.host}/ws/${this.gameId}?token=${this.token}`;
+        // A reconnecting client resumes from the last event it received
+        const since = this.lastSeq !== null ? `&since=${this.lastSeq}` : '';
+        const wsUrl = `${protocol}//${window.location.host}/ws/${this.gameId}?token=${this.token}${since}`;
         
         console.log('Connecting to WebSocket:', wsUrl);
         
@@ -209,6 +214,9 @@ class TCRGame {
         this.wsConnection.onmessage = (event) => {
             const message = JSON.parse(event.data);
             console.log('WebSocket message:', message);
+            if (typeof message.seq === 'number' && (this.lastSeq === null || message.seq > this.lastSeq)) {
+                this.lastSeq = message.seq;
+            }
             this.handleWebSocketMessage(message);
         };
         
@@ -233,7 +241,15 @@ class TCRGame {
                 this.requestGameState();
                 break;
             case 'player_joined':
-                this.addLogEntry(`${message.data.username} joined the game`);
+                this.addLogEntry(`${message.username} joined the game`);
+                break;
+            case 'player_disconnected':
+                this.addLogEntry(message.grace_seconds > 0
+                    ? `${message.player_id} disconnected and has ${message.grace_seconds}s to return`
+                    : `${message.player_id} disconnected`);
+                break;
+            case 'player_reconnected':
+                this.addLogEntry(`${message.player_id} reconnected`);
                 break;
             case 'game_end':
                 this.handleGameEnd(message);
@@ -495,6 +511,7 @@ class TCRGame {
 
     newGame() {
         this.gameId = null;
+        this.lastSeq = null;
         this.selectedTroop = null;
         this.selectedTower = null;
         this.gameState = null;